@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"lastwind/internal/cli"
+	"lastwind/internal/config"
+	"lastwind/internal/nws"
+	"lastwind/internal/term"
+)
+
+func main() {
+	cfg, err := config.LoadOrSetup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	home := cfg.DefaultLocation()
+	locName := flag.String("loc", "", "named location from the config file (default \""+home.Name+"\")")
+	lat := flag.Float64("lat", home.Latitude, "latitude")
+	lon := flag.Float64("lon", home.Longitude, "longitude")
+	place := flag.String("place", "", "place to geocode offline instead of -lat/-lon, e.g. \"Boulder, CO\"")
+	zip := flag.String("zip", "", "ZIP code to geocode offline instead of -lat/-lon")
+	zone := flag.String("zone", "", "NWS zone identifier (e.g. COZ039) instead of a point")
+	offline := flag.Bool("offline", false, "use only cached API responses")
+	flag.Parse()
+	named, hasPlace := cli.UsePlace(*place, *zip)
+	cli.UseLocation(cfg, *locName)
+
+	ctx := context.Background()
+	client, cache := cli.NewClient(*offline)
+
+	var resp nws.AlertsResponse
+	var where string
+	if *zone != "" {
+		zoneID := strings.ToUpper(*zone)
+		resp, err = client.ZoneAlerts(ctx, zoneID)
+		where = "zone " + zoneID
+	} else {
+		resp, err = client.PointAlerts(ctx, *lat, *lon)
+		where = fmt.Sprintf("%.4f, %.4f", *lat, *lon)
+		if hasPlace {
+			where = named.String()
+		}
+	}
+	if err != nil {
+		cli.Fatal("fetching alerts", err)
+	}
+
+	cli.StaleNotice(cache)
+
+	alerts := resp.Alerts()
+	fmt.Printf("\n  Active alerts for %s\n\n", where)
+	if len(alerts) == 0 {
+		fmt.Printf("  No active watches, warnings or advisories.\n\n")
+		return
+	}
+
+	for _, a := range alerts {
+		printAlert(a)
+	}
+}
+
+func printAlert(a nws.Alert) {
+	color := term.Yellow
+	if nws.SeverityRank(a.Severity) >= nws.SeverityRank("Severe") {
+		color = term.Red
+	}
+
+	fmt.Printf("  %s\n", term.Color("── "+a.Event+" ", term.Bold, color)+strings.Repeat("─", max(0, 40-len(a.Event))))
+	fmt.Printf("    Severity:     %s\n", a.Severity)
+	fmt.Printf("    Urgency:      %s\n", a.Urgency)
+	fmt.Printf("    Certainty:    %s\n", a.Certainty)
+	if a.Onset != "" {
+		fmt.Printf("    Onset:        %s\n", nws.FormatTime(a.Onset))
+	}
+	if until := a.Until(); until != "" {
+		fmt.Printf("    Ends:         %s\n", nws.FormatTime(until))
+	}
+	if zones := a.ZoneIDs(); len(zones) > 0 {
+		fmt.Printf("    Zones:        %s\n", strings.Join(zones, ", "))
+	}
+	fmt.Println()
+
+	if a.Headline != "" {
+		printWrapped(a.Headline)
+		fmt.Println()
+	}
+	if a.Description != "" {
+		printWrapped(a.Description)
+		fmt.Println()
+	}
+	if a.Instruction != "" {
+		fmt.Printf("    %s\n", term.Color("Instructions:", term.Bold))
+		printWrapped(a.Instruction)
+		fmt.Println()
+	}
+}
+
+// printWrapped wraps each paragraph of NWS text, which arrives
+// pre-wrapped with blank lines between paragraphs.
+func printWrapped(text string) {
+	for i, para := range strings.Split(text, "\n\n") {
+		if i > 0 {
+			fmt.Println()
+		}
+		for _, line := range nws.WordWrap(para, 66) {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"lastwind/internal/aviation"
+	"lastwind/internal/cli"
 	"lastwind/internal/config"
+	"lastwind/internal/metar"
 	"lastwind/internal/nws"
+	"lastwind/internal/render"
+	"lastwind/internal/term"
+	"lastwind/internal/units"
 )
 
 func main() {
@@ -16,28 +25,63 @@ func main() {
 		os.Exit(1)
 	}
 
-	lat := flag.Float64("lat", cfg.Latitude, "latitude")
-	lon := flag.Float64("lon", cfg.Longitude, "longitude")
+	home := cfg.DefaultLocation()
+	locName := flag.String("loc", "", "named location from the config file (default \""+home.Name+"\")")
+	lat := flag.Float64("lat", home.Latitude, "latitude")
+	lon := flag.Float64("lon", home.Longitude, "longitude")
+	place := flag.String("place", "", "place to geocode offline instead of -lat/-lon, e.g. \"Boulder, CO\"")
+	zip := flag.String("zip", "", "ZIP code to geocode offline instead of -lat/-lon")
+	hourly := flag.Bool("hourly", false, "show an hourly forecast table instead of 12-hour periods")
+	hours := flag.Int("hours", 24, "number of hours to show with -hourly (max 48)")
+	showTAF := flag.Bool("taf", false, "also show the latest TAF for the station as a timeline")
+	tafStation := flag.String("taf-station", "", "ICAO station for -taf (default: the configured location's station, or the nearest station with -lat, -lon, -place or -zip)")
+	runwaysFlag := flag.String("runways", "", "runways to suggest for the current wind, e.g. 12L/30R,03/21 (default from config)")
+	crosswindLimit := flag.Float64("crosswind-limit", 0, "crosswind component to flag, in the display unit (default from config, else 15 kt)")
+	qcFlag := flag.String("qc", "", "quality control policy for the runway wind and flight category: strict, lenient or off (default from config, else lenient)")
+	formatFlag := flag.String("format", "table", "output format: table, json, ndjson or csv")
+	offline := flag.Bool("offline", false, "use only cached API responses")
+	tzFlag := flag.String("tz", "station", "time zone for times: station, local, utc or an IANA name like America/Denver")
+	unitsFlag := flag.String("units", "", "unit system: us, si, metric or aviation, with optional overrides like metric,speed=kt (default from config)")
 	flag.Parse()
+	pointGiven := cli.Given("lat", "lon", "place", "zip")
+	cli.UsePlace(*place, *zip)
+	location := cli.UseLocation(cfg, *locName)
+	sys := cli.Units(cfg, *unitsFlag)
+	qc := cli.QCPolicy(cfg, *qcFlag)
+
+	format, err := render.ParseFormat(*formatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+
+	if *hours < 1 {
+		fmt.Fprintf(os.Stderr, "Error: -hours must be at least 1\n")
+		os.Exit(cli.ExitUsage)
+	}
+
+	ctx := context.Background()
+	client, cache := cli.NewClient(*offline)
 
 	// 1. Get point metadata
-	pointsURL := fmt.Sprintf("https://api.weather.gov/points/%.4f,%.4f", *lat, *lon)
-	points, err := nws.FetchJSON[nws.PointsResponse](pointsURL)
+	points, err := client.Point(ctx, *lat, *lon)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching point data: %v\n", err)
-		os.Exit(1)
+		cli.Fatal("fetching point data", err)
 	}
 
 	city := points.Properties.RelativeLocation.Properties.City
 	state := points.Properties.RelativeLocation.Properties.State
 	forecastURL := points.Properties.Forecast
+	if *hourly {
+		forecastURL = points.Properties.ForecastHourly
+	}
 	stationsURL := points.Properties.ObservationStations
+	loc := cli.TimeZone(*tzFlag, points.Properties.TimeZone)
 
 	// 2. Get nearest station
-	stations, err := nws.FetchJSON[nws.StationsResponse](stationsURL)
+	stations, err := client.Stations(ctx, stationsURL)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching stations: %v\n", err)
-		os.Exit(1)
+		cli.Fatal("fetching stations", err)
 	}
 	if len(stations.Features) == 0 {
 		fmt.Fprintf(os.Stderr, "No observation stations found\n")
@@ -45,64 +89,215 @@ func main() {
 	}
 	stationID := stations.Features[0].Properties.StationIdentifier
 	stationName := stations.Features[0].Properties.Name
+	runways := cli.Runways(cfg, stationID, *runwaysFlag)
 
 	// 3. Get current observation
-	obsURL := fmt.Sprintf("https://api.weather.gov/stations/%s/observations/latest", stationID)
-	obs, err := nws.FetchJSON[nws.ObservationResponse](obsURL)
+	obs, err := client.LatestObservation(ctx, stationID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching observations: %v\n", err)
-		os.Exit(1)
+		cli.Fatal("fetching observations", err)
 	}
 
 	// 4. Get forecast
-	forecast, err := nws.FetchJSON[nws.ForecastResponse](forecastURL)
+	forecast, err := client.Forecast(ctx, forecastURL)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching forecast: %v\n", err)
-		os.Exit(1)
+		cli.Fatal("fetching forecast", err)
+	}
+
+	// 5. Get active alerts (non-fatal)
+	alerts, err := client.PointAlerts(ctx, *lat, *lon)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not fetch alerts: %s\n", cli.Message(err))
+	}
+
+	// 6. Get the latest TAF (non-fatal)
+	var taf *metar.TAF
+	if *showTAF {
+		id := cli.PointStation(*tafStation, stationID, location, pointGiven)
+		product, err := client.LatestTAF(ctx, id)
+		if err == nil {
+			var t metar.TAF
+			if t, err = parseTAF(product); err == nil {
+				taf = &t
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch TAF for %s: %s\n", id, cli.Message(err))
+		}
+	}
+
+	periods := forecast.Properties.Periods
+	if *hourly {
+		periods = periods[:hourlyCount(len(periods), *hours)]
+	}
+
+	cli.StaleNotice(cache)
+
+	if format != render.FormatTable {
+		doc := render.ForecastDocument{
+			Location: render.LocationRecord{City: city, State: state, Latitude: *lat, Longitude: *lon},
+			Station:  render.StationRecord{ID: stationID, Name: stationName},
+			Alerts:   []render.AlertRecord{},
+			Current:  render.NewObservationRecord(stationID, obs.Properties, qc),
+		}
+		for _, a := range alerts.Alerts() {
+			doc.Alerts = append(doc.Alerts, render.NewAlertRecord(a))
+		}
+		for _, p := range periods {
+			doc.Periods = append(doc.Periods, render.NewForecastPeriodRecord(p))
+		}
+		if taf != nil {
+			r := render.NewTAFRecord(*taf)
+			doc.TAF = &r
+		}
+
+		switch format {
+		case render.FormatJSON:
+			err = render.WriteJSON(os.Stdout, doc)
+		case render.FormatNDJSON:
+			err = render.WriteNDJSON(os.Stdout, doc.Periods)
+		case render.FormatCSV:
+			err = render.WriteCSV(os.Stdout, doc.Periods)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	// Display
 	fmt.Printf("\n  %s, %s\n", city, state)
 	fmt.Printf("  Station: %s (%s)\n\n", stationName, stationID)
 
-	printCurrentConditions(obs)
-	printForecast(forecast)
+	printAlertBanner(alerts.Alerts())
+	printCurrentConditions(obs, sys, loc, qc, runways, cli.CrosswindLimit(cfg, *crosswindLimit, sys))
+	if *hourly {
+		printHourlyForecast(periods, len(forecast.Properties.Periods), sys, loc)
+	} else {
+		printForecast(forecast, sys)
+	}
+	if taf != nil {
+		printTAF(*taf, sys, loc)
+	}
 }
 
-func printCurrentConditions(obs nws.ObservationResponse) {
+func printAlertBanner(alerts []nws.Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+
+	color := term.Yellow
+	if nws.SeverityRank(alerts[0].Severity) >= nws.SeverityRank("Severe") {
+		color = term.Red
+	}
+
+	fmt.Printf("  %s\n", term.Color("━━ ACTIVE ALERTS ━━━━━━━━━━━━━━━━━━━━━━━━━━", term.Bold, color))
+	for _, a := range alerts {
+		fmt.Printf("  %s %s\n", term.Color("▲", term.Bold, color), nws.AlertSummary(a))
+	}
+	fmt.Printf("  %s\n\n", term.Color("Run `alerts` for full details.", color))
+}
+
+// printCurrentConditions prints the latest observation, suggests the best
+// runway for the wind and flags a crosswind above limit (in km/h). Values
+// the quality control policy excludes are left out of both.
+func printCurrentConditions(obs nws.ObservationResponse, sys units.System, loc *time.Location, qc nws.QCPolicy, runways []aviation.Runway, limit float64) {
 	p := obs.Properties
+	d := nws.Derive(p)
+	deg := sys.Temperature.Label()
 
-	fmt.Printf("  ── Current Conditions (%s) ──\n\n", nws.FormatTime(p.Timestamp))
+	fmt.Printf("  ── Current Conditions (%s %s) ──\n\n", nws.FormatTimeIn(p.Timestamp, loc), cli.TimestampZone(loc, p.Timestamp))
 	fmt.Printf("    %s\n", p.TextDescription)
 
 	if p.Temperature.Value != nil {
-		fmt.Printf("    Temperature:  %.0f°F", nws.CToF(*p.Temperature.Value))
-		if p.WindChill.Value != nil {
-			fmt.Printf("  (Wind Chill: %.0f°F)", nws.CToF(*p.WindChill.Value))
-		}
-		fmt.Println()
+		fmt.Printf("    Temperature:  %s%s\n", sys.Temperature.Format(*p.Temperature.Value), deg)
+	}
+	switch {
+	case p.HeatIndex.Value != nil:
+		fmt.Printf("    Feels Like:   %s%s (heat index)\n", sys.Temperature.Format(*p.HeatIndex.Value), deg)
+	case d.HeatIndex != nil:
+		fmt.Printf("    Feels Like:   %s%s (heat index)\n", sys.Temperature.Format(*d.HeatIndex), deg)
+	case p.WindChill.Value != nil:
+		fmt.Printf("    Feels Like:   %s%s (wind chill)\n", sys.Temperature.Format(*p.WindChill.Value), deg)
+	case d.WindChill != nil:
+		fmt.Printf("    Feels Like:   %s%s (wind chill)\n", sys.Temperature.Format(*d.WindChill), deg)
 	}
 	if p.Dewpoint.Value != nil {
-		fmt.Printf("    Dewpoint:     %.0f°F\n", nws.CToF(*p.Dewpoint.Value))
+		fmt.Printf("    Dewpoint:     %s%s\n", sys.Temperature.Format(*p.Dewpoint.Value), deg)
 	}
 	if p.RelativeHumidity.Value != nil {
 		fmt.Printf("    Humidity:     %.0f%%\n", *p.RelativeHumidity.Value)
 	}
-	wind := nws.FormatWind(p.WindDirection.Value, p.WindSpeed.Value, p.WindGust.Value)
+	if d.WetBulb != nil {
+		fmt.Printf("    Wet Bulb:     %s%s\n", sys.Temperature.Format(*d.WetBulb), deg)
+	}
+	if d.VaporPressure != nil {
+		fmt.Printf("    Vapor Press:  %s %s\n", sys.Pressure.FormatVaporPressure(*d.VaporPressure*100), sys.Pressure.Label())
+	}
+	if d.AbsoluteHumidity != nil {
+		fmt.Printf("    Abs Humidity: %.1f g/m³\n", *d.AbsoluteHumidity)
+	}
+	wind := nws.FormatWind(p.WindDirection.Value, p.WindSpeed.Value, p.WindGust.Value, sys.Speed)
 	if wind != "Calm" {
-		wind += " mph"
+		wind += " " + sys.Speed.Label()
 	}
 	fmt.Printf("    Wind:         %s\n", wind)
+	if w, ok := aviation.ObservationRunwayWind(runways, p, qc); ok {
+		best := w.Describe(sys.Speed)
+		if w.Exceeds(limit) {
+			best = term.Color(best+" (crosswind above the "+sys.Speed.Format(limit)+" "+sys.Speed.Label()+" limit)", term.Bold, term.Red)
+		}
+		fmt.Printf("    Runway:       %s\n", best)
+	}
 	if p.Visibility.Value != nil {
-		fmt.Printf("    Visibility:   %.1f mi\n", nws.MetersToMiles(*p.Visibility.Value))
+		fmt.Printf("    Visibility:   %s %s\n", sys.Distance.Format(*p.Visibility.Value), sys.Distance.Label())
+	}
+	if sky := p.SkyCondition(); sky != "" {
+		fmt.Printf("    Clouds:       %s\n", sky)
+	}
+	if c := p.Ceiling(); c != nil {
+		_, unit := sys.Distance.Height(*c)
+		fmt.Printf("    Ceiling:      %s %s\n", sys.Distance.FormatHeight(*c), unit)
+	}
+	if c := aviation.ObservationCategory(p, qc); c != aviation.Unknown {
+		fmt.Printf("    Flight Cat:   %s\n", c.Colored())
 	}
 	if p.Barometer.Value != nil {
-		fmt.Printf("    Barometer:    %.2f in\n", nws.PaToInHg(*p.Barometer.Value))
+		fmt.Printf("    Barometer:    %s %s\n", sys.Pressure.Format(*p.Barometer.Value), sys.Pressure.Label())
+	}
+	if p.SeaLevelPressure.Value != nil {
+		fmt.Printf("    Sea Level:    %s %s\n", sys.Pressure.Format(*p.SeaLevelPressure.Value), sys.Pressure.Label())
+	}
+	if precip := formatPrecipitation(p, sys.Distance); precip != "" {
+		fmt.Printf("    Precip:       %s\n", precip)
+	}
+	if hi, lo := p.MaxTemperatureLast24Hours.Value, p.MinTemperatureLast24Hours.Value; hi != nil && lo != nil {
+		fmt.Printf("    24h High/Low: %s%s / %s%s\n", sys.Temperature.Format(*hi), deg, sys.Temperature.Format(*lo), deg)
 	}
 	fmt.Println()
 }
 
-func printForecast(forecast nws.ForecastResponse) {
+// formatPrecipitation lists the reported precipitation totals, e.g.
+// "0.02 in (1h), 0.10 in (6h)".
+func formatPrecipitation(o nws.Observation, u units.Distance) string {
+	var parts []string
+	for _, p := range []struct {
+		v      *float64
+		period string
+	}{
+		{o.PrecipitationLastHour.Value, "1h"},
+		{o.PrecipitationLast3Hours.Value, "3h"},
+		{o.PrecipitationLast6Hours.Value, "6h"},
+	} {
+		if p.v != nil {
+			_, unit := u.Precipitation(*p.v)
+			parts = append(parts, fmt.Sprintf("%s %s (%s)", u.FormatPrecipitation(*p.v), unit, p.period))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printForecast(forecast nws.ForecastResponse, sys units.System) {
 	periods := forecast.Properties.Periods
 	if len(periods) == 0 {
 		return
@@ -121,7 +316,8 @@ func printForecast(forecast nws.ForecastResponse) {
 		if !p.IsDaytime {
 			tempLabel = "Low"
 		}
-		fmt.Printf("    %-18s %s: %d°%s  Wind: %s %s\n", p.Name, tempLabel, p.Temperature, p.TemperatureUnit, p.WindDirection, p.WindSpeed)
+		fmt.Printf("    %-18s %s: %s%s  Wind: %s\n", p.Name, tempLabel,
+			sys.Temperature.Format(p.TemperatureC()), sys.Temperature.Label(), periodWind(p, sys.Speed, true))
 		wrapped := nws.WordWrap(p.DetailedForecast, 60)
 		for _, line := range wrapped {
 			fmt.Printf("      %s\n", line)
@@ -129,3 +325,68 @@ func printForecast(forecast nws.ForecastResponse) {
 		fmt.Println()
 	}
 }
+
+// hourlyCount limits the number of hourly periods shown to the requested
+// count, at most 48.
+func hourlyCount(available, hours int) int {
+	if hours > 48 {
+		hours = 48
+	}
+	if hours > available {
+		hours = available
+	}
+	return hours
+}
+
+// periodWind formats a forecast period's wind, e.g. "NW 10 to 15", in the
+// given unit. The text is shown as issued if it can't be parsed.
+func periodWind(p nws.ForecastPeriod, u units.Speed, withUnit bool) string {
+	lo, hi, ok := p.WindSpeedRange()
+	if !ok {
+		return strings.TrimSpace(p.WindDirection + " " + p.WindSpeed)
+	}
+	speed := u.Format(lo)
+	if hi != lo {
+		speed += " to " + u.Format(hi)
+	}
+	if withUnit {
+		speed += " " + u.Label()
+	}
+	return strings.TrimSpace(p.WindDirection + " " + speed)
+}
+
+func printHourlyForecast(periods []nws.ForecastPeriod, total int, sys units.System, loc *time.Location) {
+	if len(periods) == 0 {
+		return
+	}
+
+	fmt.Printf("  ── Hourly Forecast ────────────────────────\n\n")
+
+	starts := make([]string, len(periods))
+	for i, p := range periods {
+		starts[i] = p.StartTime
+	}
+	table := render.Table{Columns: []render.Column{
+		{Header: "Time " + cli.TimestampZone(loc, starts...), Width: 14},
+		{Header: "Temp " + sys.Temperature.Label(), Width: 7, Right: true},
+		{Header: "Dwpt " + sys.Temperature.Label(), Width: 7, Right: true},
+		{Header: "Hum", Width: 6, Right: true},
+		{Header: "Precip", Width: 6, Right: true},
+		{Header: "Wind " + sys.Speed.Label(), Width: 14},
+		{Header: "Forecast", Width: 28},
+	}}
+
+	for _, p := range periods {
+		ts := nws.FormatTimeIn(p.StartTime, loc)
+		temp := sys.Temperature.Format(p.TemperatureC())
+		dwpt := nws.FmtVal(p.Dewpoint.Value, sys.Temperature.Format)
+		hum := nws.FmtVal(p.RelativeHumidity.Value, func(v float64) string { return fmt.Sprintf("%.0f%%", v) })
+		pop := nws.FmtVal(p.ProbabilityOfPrecipitation.Value, func(v float64) string { return fmt.Sprintf("%.0f%%", v) })
+		wind := periodWind(p, sys.Speed, false)
+
+		table.AddRow(ts, temp, dwpt, hum, pop, wind, p.ShortForecast)
+	}
+
+	table.Write(os.Stdout, "  ")
+	fmt.Printf("  Showing %d of %d hours\n\n", len(periods), total)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"lastwind/internal/aviation"
+	"lastwind/internal/cli"
+	"lastwind/internal/metar"
+	"lastwind/internal/nws"
+	"lastwind/internal/render"
+	"lastwind/internal/units"
+)
+
+// parseTAF decodes a TAF product, resolving its days of the month against
+// the product's issuance time.
+func parseTAF(p nws.Product) (metar.TAF, error) {
+	issued, err := time.Parse(time.RFC3339, p.IssuanceTime)
+	if err != nil {
+		issued = time.Now()
+	}
+	return metar.ParseTAF(p.ProductText, issued)
+}
+
+// printTAF prints a TAF as a timeline: the initial conditions and each
+// change group in the order they start.
+func printTAF(taf metar.TAF, sys units.System, loc *time.Location) {
+	heading := "TAF " + taf.Station
+	if taf.Amended {
+		heading += " (amended)"
+	}
+	zone := cli.ZoneAbbrev(loc, taf.Issued, taf.ValidFrom, taf.ValidTo)
+	fmt.Printf("  ── %s, issued %s %s ──\n\n", heading, taf.Issued.In(loc).Format("Jan 02 15:04"), zone)
+
+	table := render.Table{Columns: []render.Column{
+		{Header: "From " + zone, Width: 12},
+		{Header: "To " + zone, Width: 12},
+		{Header: "Change", Width: 14},
+		{Header: "Wind " + sys.Speed.Label()},
+		{Header: "Vis " + sys.Distance.Label(), Right: true},
+		{Header: "Weather"},
+		{Header: "Clouds"},
+		{Header: "Flight", Width: 6},
+	}}
+	for _, s := range taf.Segments {
+		to := "-"
+		if !s.To.IsZero() {
+			to = s.To.In(loc).Format("Jan 02 15:04")
+		}
+		table.AddRow(s.From.In(loc).Format("Jan 02 15:04"), to, changeLabel(s), segmentWind(s, sys.Speed),
+			segmentVisibility(s, sys.Distance), dash(s.WeatherCodes()), dash(s.SkyCondition()),
+			aviation.SegmentCategory(s).Colored())
+	}
+	table.Write(os.Stdout, "  ")
+	fmt.Println()
+}
+
+// changeLabel describes a segment's change group, e.g. "Becoming" or
+// "30% chance".
+func changeLabel(s metar.Segment) string {
+	switch s.Change {
+	case metar.ChangeInitial:
+		return "Initial"
+	case metar.ChangeFrom:
+		return "From"
+	case metar.ChangeBecoming:
+		return "Becoming"
+	case metar.ChangeTemporary:
+		return "Temporary"
+	case metar.ChangeProb:
+		return fmt.Sprintf("%d%% chance", s.Probability)
+	case metar.ChangeProbTempo:
+		return fmt.Sprintf("%d%% temporary", s.Probability)
+	}
+	return s.Change
+}
+
+func segmentWind(s metar.Segment, u units.Speed) string {
+	if s.Wind == nil {
+		return "-"
+	}
+	return nws.FormatWind(s.Wind.Direction, &s.Wind.Speed, s.Wind.Gust, u)
+}
+
+// segmentVisibility formats a forecast visibility, e.g. "6+" for P6SM.
+func segmentVisibility(s metar.Segment, u units.Distance) string {
+	v := s.Visibility
+	switch {
+	case s.CAVOK:
+		return "CAVOK"
+	case v == nil:
+		return "-"
+	case v.MoreThan:
+		return u.Format(v.Distance) + "+"
+	case v.LessThan:
+		return "<" + u.Format(v.Distance)
+	}
+	return u.Format(v.Distance)
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
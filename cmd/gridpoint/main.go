@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"lastwind/internal/cli"
+	"lastwind/internal/config"
+	"lastwind/internal/nws"
+	"lastwind/internal/render"
+)
+
+func main() {
+	cfg, err := config.LoadOrSetup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	home := cfg.DefaultLocation()
+	locName := flag.String("loc", "", "named location from the config file (default \""+home.Name+"\")")
+	lat := flag.Float64("lat", home.Latitude, "latitude")
+	lon := flag.Float64("lon", home.Longitude, "longitude")
+	place := flag.String("place", "", "place to geocode offline instead of -lat/-lon, e.g. \"Boulder, CO\"")
+	zip := flag.String("zip", "", "ZIP code to geocode offline instead of -lat/-lon")
+	layerName := flag.String("layer", "windSpeed", "gridpoint layer to display (e.g. windGust, skyCover, mixingHeight)")
+	hours := flag.Int("hours", 24, "number of hours to display")
+	list := flag.Bool("list", false, "list the available layers and exit")
+	offline := flag.Bool("offline", false, "use only cached API responses")
+	tzFlag := flag.String("tz", "station", "time zone for times: station, local, utc or an IANA name like America/Denver")
+	unitsFlag := flag.String("units", "", "unit system: us, si, metric or aviation, with optional overrides like metric,speed=kt (default from config)")
+	flag.Parse()
+	cli.UsePlace(*place, *zip)
+	cli.UseLocation(cfg, *locName)
+	sys := cli.Units(cfg, *unitsFlag)
+
+	if *hours < 1 {
+		fmt.Fprintf(os.Stderr, "Error: -hours must be at least 1\n")
+		os.Exit(cli.ExitUsage)
+	}
+
+	ctx := context.Background()
+	client, cache := cli.NewClient(*offline)
+
+	// 1. Get point metadata
+	points, err := client.Point(ctx, *lat, *lon)
+	if err != nil {
+		cli.Fatal("fetching point data", err)
+	}
+
+	// 2. Get raw grid data
+	grid, err := client.Gridpoint(ctx, points.Properties.ForecastGridData)
+	if err != nil {
+		cli.Fatal("fetching gridpoint data", err)
+	}
+	g := grid.Properties
+	loc := cli.TimeZone(*tzFlag, points.Properties.TimeZone)
+	cli.StaleNotice(cache)
+
+	fmt.Printf("\n  Gridpoint: %s %d,%d (updated %s %s)\n", g.GridID, g.GridX, g.GridY, nws.FormatTimeIn(g.UpdateTime, loc), cli.TimestampZone(loc, g.UpdateTime))
+
+	if *list {
+		fmt.Printf("\n  Available layers:\n")
+		for _, name := range g.LayerNames() {
+			fmt.Printf("    %s\n", name)
+		}
+		fmt.Println()
+		return
+	}
+
+	layer, ok := g.Layers[*layerName]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown layer %q (use -list to see available layers)\n", *layerName)
+		os.Exit(cli.ExitUsage)
+	}
+
+	hourly, err := layer.Hourly()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error decoding layer %s: %v\n", *layerName, err)
+		os.Exit(1)
+	}
+
+	// Skip hours that have already passed
+	now := time.Now().Truncate(time.Hour)
+	for len(hourly) > 0 && hourly[0].Time.Before(now) {
+		hourly = hourly[1:]
+	}
+
+	displayCount := *hours
+	if displayCount > len(hourly) {
+		displayCount = len(hourly)
+	}
+
+	_, unit := nws.ConvertUOM(0, layer.UOM, sys)
+	fmt.Printf("  Layer: %s (%s)\n\n", *layerName, unit)
+
+	times := make([]time.Time, displayCount)
+	for i := range times {
+		times[i] = hourly[i].Time
+	}
+	table := render.Table{Columns: []render.Column{
+		{Header: "Time " + cli.ZoneAbbrev(loc, times...), Width: 14},
+		{Header: "Value", Width: 10, Right: true},
+	}}
+
+	for i := 0; i < displayCount; i++ {
+		h := hourly[i]
+		ts := h.Time.In(loc).Format("Jan 02 15:04")
+		val := nws.FmtVal(h.Value, func(v float64) string {
+			converted, _ := nws.ConvertUOM(v, layer.UOM, sys)
+			return fmt.Sprintf("%.1f", converted)
+		})
+		table.AddRow(ts, val)
+	}
+
+	table.Write(os.Stdout, "  ")
+	fmt.Printf("  Showing %d of %d hours\n\n", displayCount, len(hourly))
+}
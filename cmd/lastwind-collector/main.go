@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"lastwind/internal/archive"
+	"lastwind/internal/cli"
+	"lastwind/internal/collector"
+	"lastwind/internal/config"
+	"lastwind/internal/nws"
+)
+
+func main() {
+	cfg, err := config.LoadOrSetup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	stations := flag.String("stations", strings.Join(cfg.Stations(), ","), "comma-separated ICAO station identifiers to poll")
+	interval := flag.Duration("interval", 10*time.Minute, "polling interval per station")
+	maxBackoff := flag.Duration("max-backoff", collector.DefaultMaxBackoff, "longest wait between polls after repeated errors")
+	flag.Parse()
+
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: -interval must be positive\n")
+		os.Exit(cli.ExitUsage)
+	}
+	if *maxBackoff <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: -max-backoff must be positive\n")
+		os.Exit(cli.ExitUsage)
+	}
+
+	var ids []string
+	for _, s := range strings.Split(*stations, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			ids = append(ids, strings.ToUpper(s))
+		}
+	}
+	if len(ids) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no stations to poll\n")
+		os.Exit(cli.ExitUsage)
+	}
+
+	arch, err := archive.OpenDefault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening archive: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	arch.Logger = logger
+	logger.Printf("polling %s every %s", strings.Join(ids, ", "), *interval)
+
+	c := &collector.Collector{
+		Stations:   ids,
+		Interval:   *interval,
+		MaxBackoff: *maxBackoff,
+		Fetch:      latestFetcher(nws.NewClient()),
+		Store:      arch,
+		Logger:     logger,
+	}
+	c.Run(ctx)
+
+	logger.Printf("shutting down")
+}
+
+func latestFetcher(client *nws.Client) collector.FetchFunc {
+	return func(ctx context.Context, station string) (nws.Observation, error) {
+		obs, err := client.LatestObservation(ctx, station)
+		return obs.Properties, err
+	}
+}
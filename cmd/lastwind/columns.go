@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"lastwind/internal/nws"
+	"lastwind/internal/render"
+	"lastwind/internal/units"
+)
+
+// extraColumn is an optional table column for the history table, selected
+// with -cols.
+type extraColumn struct {
+	name  string
+	title string
+	width int
+	unit  func(sys units.System) string
+	value func(o nws.Observation, d nws.Derived, sys units.System) string
+}
+
+func temperatureUnit(sys units.System) string { return sys.Temperature.Label() }
+
+func precipitationUnit(sys units.System) string {
+	_, unit := sys.Distance.Precipitation(0)
+	return unit
+}
+
+var extraColumns = []extraColumn{
+	{"heat-index", "HI", 7, temperatureUnit, func(_ nws.Observation, d nws.Derived, sys units.System) string {
+		return nws.FmtVal(d.HeatIndex, sys.Temperature.Format)
+	}},
+	{"wind-chill", "WC", 7, temperatureUnit, func(_ nws.Observation, d nws.Derived, sys units.System) string {
+		return nws.FmtVal(d.WindChill, sys.Temperature.Format)
+	}},
+	{"feels-like", "Feels", 8, temperatureUnit, func(_ nws.Observation, d nws.Derived, sys units.System) string {
+		return nws.FmtVal(d.FeelsLike, sys.Temperature.Format)
+	}},
+	{"wet-bulb", "WetB", 7, temperatureUnit, func(_ nws.Observation, d nws.Derived, sys units.System) string {
+		return nws.FmtVal(d.WetBulb, sys.Temperature.Format)
+	}},
+	{"vapor-pressure", "VP", 7, func(sys units.System) string { return sys.Pressure.Label() }, func(_ nws.Observation, d nws.Derived, sys units.System) string {
+		// Derived vapor pressure is in hPa; the pressure unit takes Pa.
+		return nws.FmtVal(d.VaporPressure, func(v float64) string { return sys.Pressure.FormatVaporPressure(v * 100) })
+	}},
+	{"abs-humidity", "AH", 6, func(units.System) string { return "g/m³" }, func(_ nws.Observation, d nws.Derived, _ units.System) string {
+		return nws.FmtVal(d.AbsoluteHumidity, func(v float64) string { return fmt.Sprintf("%.1f", v) })
+	}},
+	{"precip", "Pcp1h", 8, precipitationUnit, func(o nws.Observation, _ nws.Derived, sys units.System) string {
+		return nws.FmtVal(o.PrecipitationLastHour.Value, sys.Distance.FormatPrecipitation)
+	}},
+	{"ceiling", "Ceil", 7, func(sys units.System) string {
+		_, unit := sys.Distance.Height(0)
+		return unit
+	}, func(o nws.Observation, _ nws.Derived, sys units.System) string {
+		return nws.FmtVal(o.Ceiling(), sys.Distance.FormatHeight)
+	}},
+	{"sky", "Sky", 20, func(units.System) string { return "" }, func(o nws.Observation, _ nws.Derived, _ units.System) string {
+		return o.SkyCondition()
+	}},
+}
+
+// column returns the table column for c, with the unit in its header.
+func (c extraColumn) column(sys units.System) render.Column {
+	header := c.title
+	if unit := c.unit(sys); unit != "" {
+		header += " " + unit
+	}
+	return render.Column{Header: header, Width: c.width, Right: c.name != "sky"}
+}
+
+// parseColumns parses the comma-separated -cols flag. "all" selects every
+// optional column.
+func parseColumns(s string) ([]extraColumn, error) {
+	var cols []extraColumn
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			return extraColumns, nil
+		}
+		found := false
+		for _, c := range extraColumns {
+			if c.name == name {
+				cols = append(cols, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q (use %s or all)", name, columnNames())
+		}
+	}
+	return cols, nil
+}
+
+func columnNames() string {
+	names := make([]string, len(extraColumns))
+	for i, c := range extraColumns {
+		names[i] = c.name
+	}
+	return strings.Join(names, ", ")
+}
@@ -1,14 +1,23 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"log"
 	"os"
 	"strings"
 	"time"
 
+	"lastwind/internal/archive"
+	"lastwind/internal/aviation"
+	"lastwind/internal/cli"
 	"lastwind/internal/config"
 	"lastwind/internal/nws"
+	"lastwind/internal/render"
+	"lastwind/internal/stats"
+	"lastwind/internal/term"
+	"lastwind/internal/units"
 )
 
 func main() {
@@ -18,39 +27,155 @@ func main() {
 		os.Exit(1)
 	}
 
-	station := flag.String("station", cfg.Station, "ICAO station identifier (e.g. KEIK, KDEN)")
+	home := cfg.DefaultLocation()
+	locName := flag.String("loc", "", "named location from the config file (default \""+home.Name+"\")")
+	station := flag.String("station", home.Station, "ICAO station identifier (e.g. KEIK, KDEN)")
+	place := flag.String("place", "", "use the station nearest a place, geocoded offline, e.g. \"Boulder, CO\"")
+	zip := flag.String("zip", "", "use the station nearest a ZIP code, geocoded offline")
 	count := flag.Int("n", 10, "number of recent observations to display")
+	formatFlag := flag.String("format", "table", "output format: table, json, ndjson or csv")
+	windowFlag := flag.String("window", "", "statistics window for recent observations (e.g. 36h, 7d; default 3d)")
+	gustThreshold := flag.Float64("gust-threshold", 0, "gust speed counted towards gusty hours, in the display unit (default 25 mph)")
+	since := flag.String("since", "", "show archived history from this long ago (e.g. 30d, 36h)")
+	fromFlag := flag.String("from", "", "start of history to fetch and show (RFC 3339, YYYY-MM-DD or relative like -36h)")
+	toFlag := flag.String("to", "", "end of history to fetch and show (RFC 3339, YYYY-MM-DD or relative like -12h)")
+	offline := flag.Bool("offline", false, "use only cached API responses")
+	colsFlag := flag.String("cols", "", "extra table columns: heat-index, wind-chill, feels-like, wet-bulb, vapor-pressure, abs-humidity, precip, ceiling, sky or all")
+	qcFlag := flag.String("qc", "", "quality control policy: strict, lenient or off (default from config, else lenient)")
+	runwaysFlag := flag.String("runways", "", "runways for crosswind components, e.g. 12L/30R,03/21 (default from config)")
+	crosswindLimit := flag.Float64("crosswind-limit", 0, "crosswind component to flag, in the display unit (default from config, else 15 kt)")
+	metarFlag := flag.Bool("metar", false, "show the raw METAR reports, with the latest decoded group by group")
+	tzFlag := flag.String("tz", "station", "time zone for times: station, local, utc or an IANA name like America/Denver")
+	unitsFlag := flag.String("units", "", "unit system: us, si, metric or aviation, with optional overrides like metric,speed=kt (default from config)")
 	flag.Parse()
+	named, hasPlace := cli.UsePlace(*place, *zip)
+	stationGiven := cli.Given("station")
+	cli.UseLocation(cfg, *locName)
+	sys := cli.Units(cfg, *unitsFlag)
+	qc := cli.QCPolicy(cfg, *qcFlag)
+
+	format, err := render.ParseFormat(*formatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+
+	if *metarFlag && format != render.FormatTable {
+		fmt.Fprintf(os.Stderr, "Error: -metar only applies to table output (the raw METAR is in raw_message)\n")
+		os.Exit(cli.ExitUsage)
+	}
+
+	cols, err := parseColumns(*colsFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+
+	from, to, err := parseRange(*since, *fromFlag, *toFlag, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+	ranged := !from.IsZero() || !to.IsZero()
+
+	windowLen := defaultWindow
+	if *windowFlag != "" {
+		if ranged {
+			fmt.Fprintf(os.Stderr, "Error: -window cannot be combined with -since, -from or -to\n")
+			os.Exit(cli.ExitUsage)
+		}
+		if windowLen, err = archive.ParseDuration(*windowFlag); err != nil || windowLen <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: invalid -window %q\n", *windowFlag)
+			os.Exit(cli.ExitUsage)
+		}
+	}
 
 	stationID := strings.ToUpper(*station)
+	runways := cli.Runways(cfg, stationID, *runwaysFlag)
+
+	// When querying a range, network failures only mean the archive
+	// isn't refreshed.
+	fail := func(doing string, err error) {
+		if ranged {
+			fmt.Fprintf(os.Stderr, "Warning: error %s: %s (using archive only)\n", doing, cli.Message(err))
+			return
+		}
+		cli.Fatal(doing, err)
+	}
+
+	ctx := context.Background()
+	client, cache := cli.NewClient(*offline)
+
+	// With -place or -zip, use the station nearest that point unless
+	// -station was given too
+	if hasPlace && !stationGiven {
+		stations, err := client.NearbyStations(ctx, named.Latitude, named.Longitude)
+		if err == nil && len(stations.Features) == 0 {
+			err = fmt.Errorf("no observation stations near %s: %w", named, nws.ErrNotFound)
+		}
+		if err != nil {
+			cli.Fatal("finding the nearest station", err)
+		}
+		stationID = stations.Features[0].Properties.StationIdentifier
+		runways = cli.Runways(cfg, stationID, *runwaysFlag)
+	}
 
 	// Fetch station name
-	stationURL := fmt.Sprintf("https://api.weather.gov/stations/%s", stationID)
-	stationInfo, err := nws.FetchJSON[nws.StationResponse](stationURL)
+	stationName := stationID
+	stationInfo, err := client.Station(ctx, stationID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching station info: %v\n", err)
-		os.Exit(1)
+		fail("fetching station info", err)
+	} else {
+		stationName = stationInfo.Properties.Name
 	}
+	loc := cli.TimeZone(*tzFlag, stationInfo.Properties.TimeZone)
 
-	// Fetch observations (3 days worth)
-	obsURL := fmt.Sprintf("https://api.weather.gov/stations/%s/observations?limit=500", stationID)
-	obsResp, err := nws.FetchJSON[nws.ObservationsResponse](obsURL)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching observations: %v\n", err)
-		os.Exit(1)
+	// Fetch recent observations, or every page the API still has for the
+	// requested range
+	var fetched []nws.Observation
+	if ranged {
+		fetched, err = client.AllObservations(ctx, stationID, nws.ObservationOptions{Limit: 500, Start: from, End: to})
+		if err != nil {
+			fail("fetching observations", err)
+		}
+	} else {
+		obsResp, err := client.Observations(ctx, stationID, nws.ObservationOptions{Limit: 500})
+		if err != nil {
+			fail("fetching observations", err)
+		}
+		for _, f := range obsResp.Features {
+			fetched = append(fetched, f.Properties)
+		}
+	}
+
+	// Keep everything we fetched in the local archive
+	arch, archErr := archive.OpenDefault()
+	if archErr == nil {
+		arch.Logger = log.New(os.Stderr, "Warning: archive: ", 0)
+		if _, err := arch.Add(stationID, fetched); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not update archive: %v\n", err)
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: could not open archive: %v\n", archErr)
 	}
 
-	// Filter to last 3 days
-	cutoff := time.Now().UTC().AddDate(0, 0, -3)
 	var observations []nws.Observation
-	for _, f := range obsResp.Features {
-		t, err := time.Parse(time.RFC3339, f.Properties.Timestamp)
+	window := describeWindow(windowLen)
+	start, end := time.Now().Add(-windowLen), time.Time{}
+	if ranged {
+		start, end = from, to
+		window = describeRange(from, to, loc)
+	}
+	if archErr == nil {
+		// The archive holds what was just fetched, plus anything older
+		// than the API keeps.
+		observations, err = arch.Query(stationID, start, end)
 		if err != nil {
-			continue
-		}
-		if t.After(cutoff) {
-			observations = append(observations, f.Properties)
+			fmt.Fprintf(os.Stderr, "Error reading archive: %v\n", err)
+			os.Exit(1)
 		}
+	} else {
+		observations = within(fetched, start, end)
 	}
 
 	if len(observations) == 0 {
@@ -58,63 +183,327 @@ func main() {
 		os.Exit(1)
 	}
 
+	cli.StaleNotice(cache)
+
+	h := history{
+		stationID:      stationID,
+		stationName:    stationName,
+		window:         window,
+		observations:   observations,
+		qc:             qc,
+		loc:            loc,
+		runways:        runways,
+		crosswindLimit: cli.CrosswindLimit(cfg, *crosswindLimit, sys),
+	}
+	threshold := 0.0
+	if *gustThreshold > 0 {
+		threshold = *gustThreshold / sys.Speed.Convert(1)
+	}
+	h.summary = stats.Summarize(observations, stats.Options{QC: qc, GustThreshold: threshold})
+
+	if format != render.FormatTable {
+		if err := writeHistory(format, h); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	printHistory(h, *count, sys, cols, *metarFlag)
+}
+
+const defaultWindow = 3 * 24 * time.Hour
+
+// padRule pads a section heading with a rule to a fixed width.
+func padRule(heading string) string {
+	return heading + strings.Repeat("─", max(3, 44-render.VisibleWidth(heading)))
+}
+
+// describeWindow formats a window length, e.g. "3 days" or "36 hours".
+func describeWindow(d time.Duration) string {
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return "1 " + unit
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	const day = 24 * time.Hour
+	switch {
+	case d%(7*day) == 0:
+		return plural(int64(d/(7*day)), "week")
+	case d%day == 0:
+		return plural(int64(d/day), "day")
+	case d%time.Hour == 0:
+		return plural(int64(d/time.Hour), "hour")
+	}
+	return d.String()
+}
+
+// history is the set of observations displayed for a station.
+type history struct {
+	stationID    string
+	stationName  string
+	window       string
+	observations []nws.Observation
+	qc           nws.QCPolicy
+	summary      stats.Summary
+	loc          *time.Location
+	// runways are the station's runways for crosswind components; none
+	// hides the runway column.
+	runways        []aviation.Runway
+	crosswindLimit float64
+}
+
+// mark appends the quality control mark for values to a table cell: "!"
+// for rejected and "?" for suspect values excluded by the policy.
+func (h history) mark(cell string, values ...nws.NullFloat64) (string, bool) {
+	switch h.qc.Mark(values...) {
+	case "!":
+		return term.Color(cell+"!", term.Red), true
+	case "?":
+		return term.Color(cell+"?", term.Yellow), true
+	}
+	return cell, false
+}
+
+// within returns the observations timestamped in [from, to]; zero times
+// leave that end open.
+func within(observations []nws.Observation, from, to time.Time) []nws.Observation {
+	var out []nws.Observation
+	for _, o := range observations {
+		t, err := time.Parse(time.RFC3339, o.Timestamp)
+		if err != nil {
+			continue
+		}
+		if (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to)) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// parseRange turns the -since, -from and -to flags into an archive query
+// range. Both ends are zero when none of the flags are set.
+func parseRange(since, fromStr, toStr string, now time.Time) (from, to time.Time, err error) {
+	if since != "" && fromStr != "" {
+		return from, to, fmt.Errorf("-since and -from cannot be combined")
+	}
+	if since != "" {
+		d, err := archive.ParseDuration(since)
+		if err != nil {
+			return from, to, err
+		}
+		from = now.Add(-d)
+	}
+	if fromStr != "" {
+		if from, err = archive.ParseTime(fromStr, now); err != nil {
+			return from, to, err
+		}
+	}
+	if toStr != "" {
+		if to, err = archive.ParseTime(toStr, now); err != nil {
+			return from, to, err
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return from, to, fmt.Errorf("-to is before -from")
+	}
+	return from, to, nil
+}
+
+func describeRange(from, to time.Time, loc *time.Location) string {
+	const layout = "Jan 02 2006 15:04"
+	switch {
+	case to.IsZero():
+		return "since " + from.In(loc).Format(layout)
+	case from.IsZero():
+		return "until " + to.In(loc).Format(layout)
+	}
+	return from.In(loc).Format(layout) + " – " + to.In(loc).Format(layout)
+}
+
+func writeHistory(format render.Format, h history) error {
+	records := make([]render.ObservationRecord, len(h.observations))
+	for i, o := range h.observations {
+		records[i] = render.NewObservationRecord(h.stationID, o, h.qc)
+	}
+
+	switch format {
+	case render.FormatNDJSON:
+		return render.WriteNDJSON(os.Stdout, records)
+	case render.FormatCSV:
+		return render.WriteCSV(os.Stdout, records)
+	}
+
+	doc := render.HistoryDocument{
+		Station:      render.StationRecord{ID: h.stationID, Name: h.stationName},
+		Observations: records,
+		Statistics:   render.NewStatisticsRecord(h.stationID, h.window, h.summary, h.qc),
+	}
+	if e := h.summary.MaxWind; e != nil {
+		r := render.NewObservationRecord(h.stationID, e.Observation, h.qc)
+		doc.HighestWind = &r
+	}
+	if e := h.summary.MaxGust; e != nil {
+		r := render.NewObservationRecord(h.stationID, e.Observation, h.qc)
+		doc.HighestGust = &r
+	}
+	return render.WriteJSON(os.Stdout, doc)
+}
+
+func printHistory(h history, count int, sys units.System, cols []extraColumn, showMETAR bool) {
 	// Display header
-	fmt.Printf("\n  Station: %s (%s)\n\n", stationInfo.Properties.Name, stationID)
+	fmt.Printf("\n  Station: %s (%s)\n\n", h.stationName, h.stationID)
+
+	if showMETAR {
+		printMETAR(h, count, sys)
+		printStatistics(h.summary, h.window, sys, h.loc)
+		return
+	}
 
 	// Display recent observations table
-	displayCount := *count
-	if displayCount > len(observations) {
-		displayCount = len(observations)
+	displayCount := count
+	if displayCount > len(h.observations) {
+		displayCount = len(h.observations)
 	}
 
-	fmt.Printf("  ┌────────────────┬────────────────┬────────┬──────┬──────┬────────┬──────────────────────────────┐\n")
-	fmt.Printf("  │ Time           │ Wind           │ Vis mi │ Temp │ Dwpt │ Hum    │ Weather                      │\n")
-	fmt.Printf("  ├────────────────┼────────────────┼────────┼──────┼──────┼────────┼──────────────────────────────┤\n")
+	stamps := make([]string, displayCount)
+	for i, o := range h.observations[:displayCount] {
+		stamps[i] = o.Timestamp
+	}
+	columns := []render.Column{
+		{Header: "Time " + cli.TimestampZone(h.loc, stamps...), Width: 14},
+		{Header: "Wind " + sys.Speed.Label(), Width: 14},
+		{Header: "Vis " + sys.Distance.Label(), Width: 6, Right: true},
+		{Header: "Temp " + sys.Temperature.Label(), Width: 7, Right: true},
+		{Header: "Dwpt " + sys.Temperature.Label(), Width: 7, Right: true},
+		{Header: "Hum", Width: 6, Right: true},
+		{Header: "Flight", Width: 6},
+	}
+	if len(h.runways) > 0 {
+		columns = append(columns, render.Column{Header: "Runway " + sys.Speed.Label(), Width: 18})
+	}
+	for _, c := range cols {
+		columns = append(columns, c.column(sys))
+	}
+	table := render.Table{Columns: append(columns, render.Column{Header: "Weather", Width: 28})}
 
+	marked, crosswind := false, false
 	for i := 0; i < displayCount; i++ {
-		o := observations[i]
-		ts := nws.FormatTime(o.Timestamp)
-		wind := nws.FormatWind(o.WindDirection.Value, o.WindSpeed.Value, o.WindGust.Value)
-		vis := nws.FmtVal(o.Visibility.Value, func(v float64) string { return fmt.Sprintf("%.1f", nws.MetersToMiles(v)) })
-		temp := nws.FmtVal(o.Temperature.Value, func(v float64) string { return fmt.Sprintf("%.0f", nws.CToF(v)) })
-		dwpt := nws.FmtVal(o.Dewpoint.Value, func(v float64) string { return fmt.Sprintf("%.0f", nws.CToF(v)) })
+		o := h.observations[i]
+		ts := nws.FormatTimeIn(o.Timestamp, h.loc)
+		wind := nws.FormatWind(o.WindDirection.Value, o.WindSpeed.Value, o.WindGust.Value, sys.Speed)
+		vis := nws.FmtVal(o.Visibility.Value, sys.Distance.Format)
+		temp := nws.FmtVal(o.Temperature.Value, sys.Temperature.Format)
+		dwpt := nws.FmtVal(o.Dewpoint.Value, sys.Temperature.Format)
 		hum := nws.FmtVal(o.RelativeHumidity.Value, func(v float64) string { return fmt.Sprintf("%.0f%%", v) })
-		weather := nws.Truncate(o.TextDescription, 28)
 
-		fmt.Printf("  │ %-14s │ %-14s │ %6s │ %4s │ %4s │ %6s │ %-28s │\n",
-			ts, wind, vis, temp, dwpt, hum, weather)
+		flight := aviation.ObservationCategory(o, h.qc).Colored()
+
+		row := []string{ts, wind, vis, temp, dwpt, hum, flight}
+		for i, values := range [][]nws.NullFloat64{
+			{o.WindSpeed, o.WindGust}, {o.Visibility}, {o.Temperature}, {o.Dewpoint}, {o.RelativeHumidity},
+		} {
+			var m bool
+			row[i+1], m = h.mark(row[i+1], values...)
+			marked = marked || m
+		}
+		if len(h.runways) > 0 {
+			cell := "-"
+			if w, ok := aviation.ObservationRunwayWind(h.runways, o, h.qc); ok {
+				cell = w.Format(sys.Speed)
+				if w.Exceeds(h.crosswindLimit) {
+					cell = term.Color(cell+"*", term.Bold, term.Red)
+					crosswind = true
+				}
+			} else if v := h.qc.Value(o.WindSpeed); v != nil && *v == 0 {
+				cell = "Calm"
+			}
+			row = append(row, cell)
+		}
+		if len(cols) > 0 {
+			d := nws.Derive(o)
+			for _, c := range cols {
+				row = append(row, c.value(o, d, sys))
+			}
+		}
+		table.AddRow(append(row, o.TextDescription)...)
+	}
+
+	table.Write(os.Stdout, "  ")
+	if marked {
+		fmt.Printf("  ! rejected, ? questioned by quality control (excluded from statistics)\n")
+	}
+	if crosswind {
+		fmt.Printf("  * crosswind component above the %s %s limit\n", sys.Speed.Format(h.crosswindLimit), sys.Speed.Label())
 	}
+	fmt.Printf("  Showing %d of %d observations (%s)\n\n", displayCount, len(h.observations), h.window)
 
-	fmt.Printf("  └────────────────┴────────────────┴────────┴──────┴──────┴────────┴──────────────────────────────┘\n")
-	fmt.Printf("  Showing %d of %d observations (3 days)\n\n", displayCount, len(observations))
+	printStatistics(h.summary, h.window, sys, h.loc)
+}
 
-	// Find highest wind and gust
-	maxSpeed, maxGust := 0.0, 0.0
-	var maxSpeedObs, maxGustObs nws.Observation
+// printStatistics prints the statistics section, with the time of each
+// extreme in loc.
+func printStatistics(s stats.Summary, window string, sys units.System, loc *time.Location) {
+	deg := sys.Temperature.Label()
+	speed := sys.Speed.Label()
+	at := func(e *stats.Extreme) string { return nws.FormatTimeIn(e.Observation.Timestamp, loc) }
+	wind := func(e *stats.Extreme) string {
+		return strings.TrimSpace(fmt.Sprintf("%s %s %s", sys.Speed.Format(e.Value), speed, nws.CompassDir(e.Observation.WindDirection.Value)))
+	}
 
-	for _, o := range observations {
-		if o.WindSpeed.Value != nil && *o.WindSpeed.Value > maxSpeed {
-			maxSpeed = *o.WindSpeed.Value
-			maxSpeedObs = o
-		}
-		if o.WindGust.Value != nil && *o.WindGust.Value > maxGust {
-			maxGust = *o.WindGust.Value
-			maxGustObs = o
+	// Label the zone the extremes' times are shown in.
+	var stamps []string
+	for _, e := range []*stats.Extreme{s.MinTemperature, s.MaxTemperature, s.MinHumidity, s.MaxHumidity, s.MaxWind, s.MaxGust, s.MinPressure, s.MaxPressure} {
+		if e != nil {
+			stamps = append(stamps, e.Observation.Timestamp)
 		}
 	}
+	if zone := cli.TimestampZone(loc, stamps...); zone != "" {
+		window += ", " + zone
+	}
 
-	fmt.Printf("  ── 3-Day Extremes ─────────────────────────\n")
-	if maxSpeed > 0 {
-		fmt.Printf("  Highest Wind:  %.0f mph %s (%s)\n",
-			nws.KmhToMph(maxSpeed), nws.CompassDir(maxSpeedObs.WindDirection.Value), nws.FormatTime(maxSpeedObs.Timestamp))
+	fmt.Printf("  %s\n", padRule("── Statistics ("+window+") "))
+	if s.MaxTemperature != nil {
+		fmt.Printf("  High Temp:     %s%s (%s)\n", sys.Temperature.Format(s.MaxTemperature.Value), deg, at(s.MaxTemperature))
+		fmt.Printf("  Low Temp:      %s%s (%s)\n", sys.Temperature.Format(s.MinTemperature.Value), deg, at(s.MinTemperature))
+		fmt.Printf("  Mean Temp:     %s%s\n", sys.Temperature.Format(*s.MeanTemperature), deg)
+	}
+	if s.MaxHumidity != nil {
+		fmt.Printf("  High Humidity: %.0f%% (%s)\n", s.MaxHumidity.Value, at(s.MaxHumidity))
+		fmt.Printf("  Low Humidity:  %.0f%% (%s)\n", s.MinHumidity.Value, at(s.MinHumidity))
+	}
+	if s.MaxWind != nil {
+		fmt.Printf("  Highest Wind:  %s (%s)\n", wind(s.MaxWind), at(s.MaxWind))
 	} else {
 		fmt.Printf("  Highest Wind:  No sustained winds recorded\n")
 	}
-	if maxGust > 0 {
-		fmt.Printf("  Highest Gust:  %.0f mph %s (%s)\n",
-			nws.KmhToMph(maxGust), nws.CompassDir(maxGustObs.WindDirection.Value), nws.FormatTime(maxGustObs.Timestamp))
+	if s.MaxGust != nil {
+		fmt.Printf("  Highest Gust:  %s (%s)\n", wind(s.MaxGust), at(s.MaxGust))
 	} else {
 		fmt.Printf("  Highest Gust:  No gusts recorded\n")
 	}
+	if s.MeanWindSpeed != nil {
+		fmt.Printf("  Mean Wind:     %s %s", sys.Speed.Format(*s.MeanWindSpeed), speed)
+		if s.PrevailingDirection != nil {
+			fmt.Printf(", prevailing %s", nws.CompassDir(s.PrevailingDirection))
+		}
+		fmt.Println()
+	}
+	if s.MaxPressure != nil {
+		fmt.Printf("  Pressure:      %s – %s %s (low %s, high %s)\n",
+			sys.Pressure.Format(s.MinPressure.Value), sys.Pressure.Format(s.MaxPressure.Value), sys.Pressure.Label(),
+			at(s.MinPressure), at(s.MaxPressure))
+	}
+	fmt.Printf("  Gusty Hours:   %d with gusts of %s %s or more\n", s.GustyHours, sys.Speed.Format(s.GustThreshold), speed)
+	fmt.Printf("  Calm:          %d of %d observations\n", s.CalmCount, s.Count)
+	var hours []string
+	for _, c := range aviation.Categories {
+		if n := s.CategoryHours[c]; n > 0 {
+			hours = append(hours, fmt.Sprintf("%s %dh", c.Colored(), n))
+		}
+	}
+	if len(hours) > 0 {
+		fmt.Printf("  Flight Cat:    %s\n", strings.Join(hours, ", "))
+	}
 	fmt.Println()
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"lastwind/internal/cli"
+	"lastwind/internal/metar"
+	"lastwind/internal/nws"
+	"lastwind/internal/render"
+	"lastwind/internal/units"
+)
+
+// printMETAR prints the most recent raw METAR next to its decoded groups,
+// followed by the raw reports of the next count-1 observations.
+func printMETAR(h history, count int, sys units.System) {
+	var reports []nws.Observation
+	for _, o := range h.observations {
+		if len(reports) == count {
+			break
+		}
+		if o.RawMessage != "" {
+			reports = append(reports, o)
+		}
+	}
+	if len(reports) == 0 {
+		fmt.Printf("  No METAR reports in these observations.\n\n")
+		return
+	}
+
+	latest := reports[0]
+	fmt.Printf("  %s\n", padRule("── METAR ("+nws.FormatTimeIn(latest.Timestamp, h.loc)+" "+cli.TimestampZone(h.loc, latest.Timestamp)+") "))
+	fmt.Printf("  %s\n\n", latest.RawMessage)
+
+	report, err := metar.Parse(latest.RawMessage)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not decode METAR: %v\n", err)
+	} else {
+		table := render.Table{Columns: []render.Column{{Header: "Group"}, {Header: "Decoded"}}}
+		for _, l := range report.Explain(sys) {
+			table.AddRow(l.Raw, l.Meaning)
+		}
+		table.Write(os.Stdout, "  ")
+		fmt.Println()
+	}
+
+	if len(reports) > 1 {
+		fmt.Printf("  %s\n", padRule("── Earlier Reports "))
+		for _, o := range reports[1:] {
+			fmt.Printf("  %-14s %s\n", nws.FormatTimeIn(o.Timestamp, h.loc), o.RawMessage)
+		}
+		fmt.Println()
+	}
+}
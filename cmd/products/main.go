@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"lastwind/internal/cli"
+	"lastwind/internal/config"
+	"lastwind/internal/nws"
+	"lastwind/internal/render"
+	"lastwind/internal/term"
+)
+
+func main() {
+	cfg, err := config.LoadOrSetup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	home := cfg.DefaultLocation()
+	locName := flag.String("loc", "", "named location from the config file (default \""+home.Name+"\")")
+	lat := flag.Float64("lat", home.Latitude, "latitude")
+	lon := flag.Float64("lon", home.Longitude, "longitude")
+	place := flag.String("place", "", "place to geocode offline instead of -lat/-lon, e.g. \"Boulder, CO\"")
+	zip := flag.String("zip", "", "ZIP code to geocode offline instead of -lat/-lon")
+	office := flag.String("office", "", "forecast office, e.g. BOU (default: the office for the location)")
+	productType := flag.String("type", "AFD", "product type, e.g. AFD (forecast discussion), HWO (hazardous weather outlook) or ZFP")
+	list := flag.Bool("list", false, "list recent products of the type instead of showing the latest")
+	count := flag.Int("n", 10, "number of products to list with -list")
+	id := flag.String("id", "", "show the product with this ID (from -list) instead of the latest")
+	sectionFlag := flag.String("section", "", "show only sections whose names contain these, e.g. aviation,fire")
+	offline := flag.Bool("offline", false, "use only cached API responses")
+	tzFlag := flag.String("tz", "station", "time zone for times: station, local, utc or an IANA name like America/Denver")
+	flag.Parse()
+	cli.UsePlace(*place, *zip)
+	cli.UseLocation(cfg, *locName)
+
+	ctx := context.Background()
+	client, cache := cli.NewClient(*offline)
+
+	// 1. Get the forecast office and time zone for the point
+	points, err := client.Point(ctx, *lat, *lon)
+	if err != nil {
+		cli.Fatal("fetching point data", err)
+	}
+	loc := cli.TimeZone(*tzFlag, points.Properties.TimeZone)
+	officeID := strings.ToUpper(*office)
+	if officeID == "" {
+		officeID = points.Office()
+	}
+	kind := strings.ToUpper(*productType)
+
+	// 2. List the recent products
+	if *list {
+		products, err := client.Products(ctx, kind, officeID)
+		if err != nil {
+			cli.Fatal("fetching products", err)
+		}
+		cli.StaleNotice(cache)
+		printList(products.Graph, kind, officeID, *count, loc)
+		return
+	}
+
+	// 3. Get the product
+	var product nws.Product
+	if *id != "" {
+		product, err = client.Product(ctx, *id)
+	} else {
+		product, err = client.LatestProduct(ctx, kind, officeID)
+	}
+	if err != nil {
+		cli.Fatal("fetching product", err)
+	}
+	cli.StaleNotice(cache)
+
+	sections := product.Sections()
+	if *sectionFlag != "" {
+		names := strings.Split(*sectionFlag, ",")
+		matched := nws.FilterSections(sections, names)
+		if len(matched) == 0 {
+			fmt.Fprintf(os.Stderr, "No section matching %q in %s; it has: %s\n", *sectionFlag, product.ProductCode, orNone(nws.SectionNames(sections)))
+			os.Exit(1)
+		}
+		sections = matched
+	}
+	printProduct(product, sections, loc)
+}
+
+func printList(products []nws.ProductSummary, kind, office string, count int, loc *time.Location) {
+	fmt.Printf("\n  %s products from %s\n\n", kind, office)
+	if len(products) == 0 {
+		fmt.Printf("  None issued recently.\n\n")
+		return
+	}
+	if count > 0 && len(products) > count {
+		products = products[:count]
+	}
+
+	issued := make([]string, len(products))
+	for i, p := range products {
+		issued[i] = p.IssuanceTime
+	}
+	table := render.Table{Columns: []render.Column{
+		{Header: "Issued " + cli.TimestampZone(loc, issued...), Width: 14},
+		{Header: "Product"},
+		{Header: "ID"},
+	}}
+	for _, p := range products {
+		table.AddRow(nws.FormatTimeIn(p.IssuanceTime, loc), p.ProductName, p.ID)
+	}
+	table.Write(os.Stdout, "  ")
+	fmt.Printf("  Run `products -id <ID>` to read one.\n\n")
+}
+
+// printProduct prints a product's sections under headings, keeping the
+// forecasters' line breaks.
+func printProduct(p nws.Product, sections []nws.Section, loc *time.Location) {
+	title := p.ProductName
+	if title == "" {
+		title = p.ProductCode
+	}
+	fmt.Printf("\n  %s\n", term.Color(title, term.Bold))
+	fmt.Printf("  %s, issued %s %s\n\n", p.IssuingOffice, nws.FormatTimeIn(p.IssuanceTime, loc), cli.TimestampZone(loc, p.IssuanceTime))
+
+	for _, s := range sections {
+		if s.Name != "" {
+			heading := "── " + strings.TrimSpace(s.Name+" "+s.Qualifier) + " "
+			fmt.Printf("  %s\n\n", term.Color(heading, term.Bold)+strings.Repeat("─", max(3, 44-render.VisibleWidth(heading))))
+		}
+		if s.Text == "" {
+			continue
+		}
+		blank := false
+		for _, line := range strings.Split(s.Text, "\n") {
+			// Collapse runs of blank lines.
+			if line == "" {
+				if !blank {
+					fmt.Println()
+				}
+				blank = true
+				continue
+			}
+			blank = false
+			fmt.Printf("    %s\n", line)
+		}
+		fmt.Println()
+	}
+}
+
+func orNone(names []string) string {
+	if len(names) == 0 {
+		return "no named sections"
+	}
+	return strings.Join(names, ", ")
+}
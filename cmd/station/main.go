@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"lastwind/internal/cli"
+	"lastwind/internal/config"
+	"lastwind/internal/nws"
+	"lastwind/internal/render"
+	"lastwind/internal/units"
+)
+
+func main() {
+	cfg, err := config.LoadOrSetup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	home := cfg.DefaultLocation()
+	locName := flag.String("loc", "", "named location from the config file (default \""+home.Name+"\")")
+	station := flag.String("station", home.Station, "station identifier")
+	lat := flag.Float64("lat", home.Latitude, "latitude to measure nearby stations from")
+	lon := flag.Float64("lon", home.Longitude, "longitude to measure nearby stations from")
+	place := flag.String("place", "", "place to geocode offline instead of -lat/-lon, e.g. \"Boulder, CO\"")
+	zip := flag.String("zip", "", "ZIP code to geocode offline instead of -lat/-lon")
+	nearby := flag.Int("n", 5, "number of nearby stations to list")
+	search := flag.String("search", "", "find stations whose name or identifier contains this text")
+	state := flag.String("state", "", "search the stations in these states (comma-separated, e.g. CO,WY; default: the state of -lat/-lon)")
+	radius := flag.Float64("radius", 0, "find stations within this distance of -lat/-lon (display distance unit); add -state for a radius reaching into other states")
+	sortBy := flag.String("sort", "distance", "sort search results by "+strings.Join(nws.StationSortKeys, ", "))
+	formatFlag := flag.String("format", "table", "output format: table, json, ndjson or csv (ndjson and csv list nearby stations)")
+	offline := flag.Bool("offline", false, "use only cached API responses")
+	unitsFlag := flag.String("units", "", "unit system: us, si, metric or aviation, with optional overrides like metric,speed=kt (default from config)")
+	flag.Parse()
+	cli.UsePlace(*place, *zip)
+	cli.UseLocation(cfg, *locName)
+	sys := cli.Units(cfg, *unitsFlag)
+
+	format, err := render.ParseFormat(*formatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+
+	if err := nws.SortStations(nil, *sortBy); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+
+	stationID := strings.ToUpper(*station)
+	ctx := context.Background()
+	client, cache := cli.NewClient(*offline)
+
+	if *search != "" || *state != "" || *radius > 0 {
+		q := query{text: *search, radius: *radius / sys.Distance.Convert(1), sortBy: *sortBy}
+		q.states = strings.FieldsFunc(*state, func(r rune) bool { return r == ',' || r == ' ' })
+		results := searchStations(ctx, client, q, *lat, *lon)
+		cli.StaleNotice(cache)
+		writeSearch(format, results, sys)
+		return
+	}
+
+	// 1. Get station metadata
+	info, err := client.Station(ctx, stationID)
+	if err != nil {
+		cli.Fatal("fetching station info", err)
+	}
+
+	// 2. The forecast office comes from the point at the station (non-fatal)
+	var office string
+	if slat, slon, ok := info.Geometry.LatLon(); ok {
+		points, err := client.Point(ctx, slat, slon)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not look up forecast office: %s\n", cli.Message(err))
+		} else {
+			office = points.Office()
+		}
+	}
+
+	// 3. Get stations near the configured location (non-fatal)
+	var near []nws.StationDistance
+	stations, err := client.NearbyStations(ctx, *lat, *lon)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not fetch nearby stations: %s\n", cli.Message(err))
+	}
+	for _, d := range nws.ByDistance(stations.Features, *lat, *lon) {
+		if len(near) == *nearby {
+			break
+		}
+		if d.Station.Properties.StationIdentifier != stationID {
+			near = append(near, d)
+		}
+	}
+
+	cli.StaleNotice(cache)
+
+	if format != render.FormatTable {
+		doc := render.StationDocument{
+			Station: render.NewStationInfoRecord(info, office),
+			Nearby:  []render.NearbyStationRecord{},
+		}
+		for _, d := range near {
+			doc.Nearby = append(doc.Nearby, render.NewNearbyStationRecord(d))
+		}
+
+		switch format {
+		case render.FormatJSON:
+			err = render.WriteJSON(os.Stdout, doc)
+		case render.FormatNDJSON:
+			err = render.WriteNDJSON(os.Stdout, doc.Nearby)
+		case render.FormatCSV:
+			err = render.WriteCSV(os.Stdout, doc.Nearby)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	printStation(info, office, sys)
+	printNearby(near, *lat, *lon, sys)
+}
+
+func printStation(info nws.StationResponse, office string, sys units.System) {
+	s := info.Properties
+	orDash := func(v string) string {
+		if v == "" {
+			return "-"
+		}
+		return v
+	}
+
+	fmt.Printf("\n  Station: %s (%s)\n\n", s.Name, s.StationIdentifier)
+	if lat, lon, ok := info.Geometry.LatLon(); ok {
+		fmt.Printf("    Coordinates:   %.4f, %.4f\n", lat, lon)
+	}
+	if s.Elevation.Value != nil {
+		_, unit := sys.Distance.Height(*s.Elevation.Value)
+		fmt.Printf("    Elevation:     %s %s\n", sys.Distance.FormatHeight(*s.Elevation.Value), unit)
+	}
+	tz := orDash(s.TimeZone)
+	if loc, err := time.LoadLocation(s.TimeZone); err == nil && s.TimeZone != "" {
+		tz += " (" + cli.ZoneAbbrev(loc, time.Now()) + ")"
+	}
+	fmt.Printf("    Time Zone:     %s\n", tz)
+	fmt.Printf("    Office:        %s\n", orDash(office))
+	fmt.Printf("    Forecast Zone: %s\n", orDash(nws.ZoneID(s.Forecast)))
+	fmt.Printf("    County:        %s\n", orDash(nws.ZoneID(s.County)))
+	fmt.Printf("    Fire Zone:     %s\n", orDash(nws.ZoneID(s.FireWeatherZone)))
+	fmt.Println()
+}
+
+func printNearby(near []nws.StationDistance, lat, lon float64, sys units.System) {
+	if len(near) == 0 {
+		return
+	}
+
+	fmt.Printf("  ── Nearby Stations ────────────────────────\n")
+	fmt.Printf("  Measured from %.4f, %.4f\n\n", lat, lon)
+
+	table := render.Table{Columns: []render.Column{
+		{Header: "Station", Width: 8},
+		{Header: "Name", Width: 36},
+		{Header: "Dist " + sys.Distance.Label(), Width: 8, Right: true},
+		{Header: "Bearing", Width: 10},
+	}}
+	for _, d := range near {
+		table.AddRow(d.Station.Properties.StationIdentifier, d.Station.Properties.Name, sys.Distance.Format(d.Distance), formatBearing(d.Bearing))
+	}
+	table.Write(os.Stdout, "  ")
+	fmt.Println()
+}
+
+// query is a station search. radius is in metres; zero means any
+// distance.
+type query struct {
+	text   string
+	states []string
+	radius float64
+	sortBy string
+}
+
+// searchStations finds the stations matching q, measured from lat, lon.
+// Without states it searches the point's state, plus the stations the API
+// lists for the point, which include any just across a state line.
+func searchStations(ctx context.Context, client *nws.Client, q query, lat, lon float64) []nws.StationDistance {
+	var stations []nws.StationResponse
+	if len(q.states) > 0 {
+		var err error
+		stations, err = client.AllStations(ctx, nws.StationOptions{States: q.states, Limit: 500})
+		if err != nil {
+			cli.Fatal("searching stations", err)
+		}
+	} else {
+		points, err := client.Point(ctx, lat, lon)
+		if err != nil {
+			cli.Fatal("fetching point data", err)
+		}
+		if state := points.Properties.RelativeLocation.Properties.State; state != "" {
+			stations, err = client.AllStations(ctx, nws.StationOptions{States: []string{state}, Limit: 500})
+			if err != nil {
+				cli.Fatal("searching stations", err)
+			}
+		}
+		if points.Properties.ObservationStations != "" {
+			resp, err := client.Stations(ctx, points.Properties.ObservationStations)
+			if err != nil {
+				cli.Fatal("fetching nearby stations", err)
+			}
+			stations = mergeStations(stations, resp.Features)
+		}
+	}
+
+	if q.text != "" {
+		stations = nws.MatchStations(stations, q.text)
+	}
+	results := nws.ByDistance(stations, lat, lon)
+	if q.radius > 0 {
+		results = nws.WithinRadius(results, q.radius)
+	}
+	nws.SortStations(results, q.sortBy)
+	return results
+}
+
+// mergeStations appends the stations in more that aren't already in
+// stations.
+func mergeStations(stations, more []nws.StationResponse) []nws.StationResponse {
+	seen := make(map[string]bool, len(stations))
+	for _, s := range stations {
+		seen[s.Properties.StationIdentifier] = true
+	}
+	for _, s := range more {
+		if id := s.Properties.StationIdentifier; !seen[id] {
+			seen[id] = true
+			stations = append(stations, s)
+		}
+	}
+	return stations
+}
+
+func writeSearch(format render.Format, results []nws.StationDistance, sys units.System) {
+	if format == render.FormatTable {
+		printSearch(results, sys)
+		return
+	}
+
+	doc := render.StationSearchDocument{Stations: []render.NearbyStationRecord{}}
+	for _, d := range results {
+		doc.Stations = append(doc.Stations, render.NewNearbyStationRecord(d))
+	}
+	var err error
+	switch format {
+	case render.FormatJSON:
+		err = render.WriteJSON(os.Stdout, doc)
+	case render.FormatNDJSON:
+		err = render.WriteNDJSON(os.Stdout, doc.Stations)
+	case render.FormatCSV:
+		err = render.WriteCSV(os.Stdout, doc.Stations)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printSearch(results []nws.StationDistance, sys units.System) {
+	fmt.Println()
+	if len(results) == 0 {
+		fmt.Printf("  No matching stations.\n\n")
+		return
+	}
+
+	_, height := sys.Distance.Height(0)
+	table := render.Table{Columns: []render.Column{
+		{Header: "Station", Width: 8},
+		{Header: "Name", Width: 36},
+		{Header: "Elev " + height, Width: 8, Right: true},
+		{Header: "Dist " + sys.Distance.Label(), Width: 8, Right: true},
+		{Header: "Bearing", Width: 10},
+	}}
+	for _, d := range results {
+		s := d.Station.Properties
+		elev := nws.FmtVal(s.Elevation.Value, sys.Distance.FormatHeight)
+		table.AddRow(s.StationIdentifier, s.Name, elev, sys.Distance.Format(d.Distance), formatBearing(d.Bearing))
+	}
+	table.Write(os.Stdout, "  ")
+	if len(results) == 1 {
+		fmt.Printf("  1 station\n\n")
+	} else {
+		fmt.Printf("  %d stations\n\n", len(results))
+	}
+}
+
+// formatBearing formats a bearing in degrees with its compass point, e.g.
+// " 67° ENE".
+func formatBearing(deg float64) string {
+	b := math.Mod(math.Round(deg), 360)
+	return fmt.Sprintf("%3.0f° %s", b, nws.CompassDir(&b))
+}
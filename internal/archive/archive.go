@@ -0,0 +1,220 @@
+// Package archive stores observations on disk so history can be queried
+// beyond the roughly one-week window kept by the NWS API.
+//
+// Each station has an append-only file of JSON lines, one nws.Observation
+// per line, under the lastwind config directory. Each line is appended
+// with a single write, so concurrent writers don't interleave records;
+// lines that still can't be parsed, such as one cut short by a crash, are
+// skipped when reading.
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"lastwind/internal/config"
+	"lastwind/internal/nws"
+)
+
+// Archive is a directory of per-station observation files.
+type Archive struct {
+	dir string
+
+	// Logger, if set, receives a warning when unreadable lines are
+	// skipped.
+	Logger *log.Logger
+}
+
+// DefaultDir returns the archive directory under config.Dir().
+func DefaultDir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "archive"), nil
+}
+
+// Open returns the archive in dir, creating the directory if needed.
+func Open(dir string) (*Archive, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Archive{dir: dir}, nil
+}
+
+// OpenDefault opens the archive in DefaultDir.
+func OpenDefault() (*Archive, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return Open(dir)
+}
+
+func (a *Archive) path(station string) string {
+	return filepath.Join(a.dir, strings.ToUpper(station)+".jsonl")
+}
+
+// Add appends the observations not already archived for the station,
+// deduplicating by timestamp. It returns the number of new observations.
+func (a *Archive) Add(station string, observations []nws.Observation) (int, error) {
+	existing, err := a.read(station)
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[int64]bool, len(existing))
+	for _, o := range existing {
+		if t, err := time.Parse(time.RFC3339, o.Timestamp); err == nil {
+			seen[t.Unix()] = true
+		}
+	}
+
+	var fresh []nws.Observation
+	for _, o := range observations {
+		t, err := time.Parse(time.RFC3339, o.Timestamp)
+		if err != nil || seen[t.Unix()] {
+			continue
+		}
+		seen[t.Unix()] = true
+		fresh = append(fresh, o)
+	}
+	if len(fresh) == 0 {
+		return 0, nil
+	}
+	sortOldestFirst(fresh)
+
+	f, err := os.OpenFile(a.path(station), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// Start on a new line if the last write was cut short, so the partial
+	// line doesn't swallow the next record.
+	prefix, err := needsNewline(f)
+	if err != nil {
+		return 0, err
+	}
+	for i, o := range fresh {
+		line, err := json.Marshal(o)
+		if err != nil {
+			return i, err
+		}
+		if prefix {
+			line = append([]byte("\n"), line...)
+			prefix = false
+		}
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			return i, err
+		}
+	}
+	return len(fresh), nil
+}
+
+// needsNewline reports whether f is non-empty and doesn't end in a
+// newline.
+func needsNewline(f *os.File) (bool, error) {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return false, err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return false, err
+	}
+	return last[0] != '\n', nil
+}
+
+// Query returns the station's archived observations with timestamps in
+// [from, to], newest first like the API. A zero from or to leaves that end
+// of the range open.
+func (a *Archive) Query(station string, from, to time.Time) ([]nws.Observation, error) {
+	all, err := a.read(station)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool, len(all))
+	var out []nws.Observation
+	for _, o := range all {
+		t, err := time.Parse(time.RFC3339, o.Timestamp)
+		if err != nil || seen[t.Unix()] {
+			continue
+		}
+		seen[t.Unix()] = true
+		if !from.IsZero() && t.Before(from) {
+			continue
+		}
+		if !to.IsZero() && t.After(to) {
+			continue
+		}
+		out = append(out, o)
+	}
+	sortOldestFirst(out)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, nil
+}
+
+// Stations returns the identifiers of every archived station.
+func (a *Archive) Stations() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(a.dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	stations := make([]string, len(matches))
+	for i, m := range matches {
+		stations[i] = strings.TrimSuffix(filepath.Base(m), ".jsonl")
+	}
+	sort.Strings(stations)
+	return stations, nil
+}
+
+func (a *Archive) read(station string) ([]nws.Observation, error) {
+	f, err := os.Open(a.path(station))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []nws.Observation
+	skipped, first := 0, 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var o nws.Observation
+		if err := json.Unmarshal(scanner.Bytes(), &o); err != nil {
+			if skipped == 0 {
+				first = line
+			}
+			skipped++
+			continue
+		}
+		out = append(out, o)
+	}
+	if skipped > 0 && a.Logger != nil {
+		a.Logger.Printf("skipped %d unreadable line(s) in %s, the first at line %d", skipped, a.path(station), first)
+	}
+	return out, scanner.Err()
+}
+
+func sortOldestFirst(observations []nws.Observation) {
+	sort.SliceStable(observations, func(i, j int) bool {
+		ti, _ := time.Parse(time.RFC3339, observations[i].Timestamp)
+		tj, _ := time.Parse(time.RFC3339, observations[j].Timestamp)
+		return ti.Before(tj)
+	})
+}
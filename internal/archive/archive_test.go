@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"lastwind/internal/nws"
+)
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func obs(ts string, speed float64) nws.Observation {
+	return nws.Observation{Timestamp: ts, WindSpeed: nws.NullFloat64{Value: floatPtr(speed)}}
+}
+
+func TestAddAndQuery(t *testing.T) {
+	a, err := Open(filepath.Join(t.TempDir(), "archive"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	n, err := a.Add("kden", []nws.Observation{
+		obs("2026-02-17T10:53:00+00:00", 10),
+		obs("2026-02-17T08:53:00+00:00", 8),
+		obs("2026-02-17T09:53:00+00:00", 9),
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("Add() = %d, %v, want 3", n, err)
+	}
+
+	// Same instant in a different offset, one new, one unparseable
+	n, err = a.Add("KDEN", []nws.Observation{
+		obs("2026-02-17T03:53:00-07:00", 10),
+		obs("2026-02-17T11:53:00+00:00", 11),
+		obs("garbage", 0),
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("second Add() = %d, %v, want 1", n, err)
+	}
+
+	all, err := a.Query("KDEN", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("len(Query()) = %d, want 4", len(all))
+	}
+	if all[0].Timestamp != "2026-02-17T11:53:00+00:00" || all[3].Timestamp != "2026-02-17T08:53:00+00:00" {
+		t.Errorf("Query() not newest first: %s .. %s", all[0].Timestamp, all[3].Timestamp)
+	}
+	if all[0].WindSpeed.Value == nil || *all[0].WindSpeed.Value != 11 {
+		t.Errorf("values not round-tripped")
+	}
+
+	from := time.Date(2026, 2, 17, 9, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 17, 10, 53, 0, 0, time.UTC)
+	ranged, err := a.Query("KDEN", from, to)
+	if err != nil {
+		t.Fatalf("Query(range) error = %v", err)
+	}
+	if len(ranged) != 2 {
+		t.Errorf("len(Query(range)) = %d, want 2", len(ranged))
+	}
+}
+
+func TestQuery_MissingStation(t *testing.T) {
+	a, _ := Open(t.TempDir())
+	got, err := a.Query("KXXX", time.Time{}, time.Time{})
+	if err != nil || len(got) != 0 {
+		t.Errorf("Query() = %v, %v, want empty", got, err)
+	}
+}
+
+func TestQuery_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	a, _ := Open(dir)
+	var warnings strings.Builder
+	a.Logger = log.New(&warnings, "", 0)
+	os.WriteFile(filepath.Join(dir, "KDEN.jsonl"), []byte(`{"timestamp":"2026-02-17T10:53:00+00:00"}
+not json
+{"timestamp":"2026-02-17T11:5`), 0644)
+
+	got, err := a.Query("KDEN", time.Time{}, time.Time{})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("Query() = %v, %v, want the one readable observation", got, err)
+	}
+	if !strings.Contains(warnings.String(), "skipped 2 unreadable line(s)") || !strings.Contains(warnings.String(), "line 2") {
+		t.Errorf("warning = %q, want the count and first line", warnings.String())
+	}
+
+	// A record appended after a truncated line starts on its own line.
+	if n, err := a.Add("KDEN", []nws.Observation{obs("2026-02-17T12:53:00+00:00", 12)}); err != nil || n != 1 {
+		t.Fatalf("Add() = %d, %v, want 1", n, err)
+	}
+	got, err = a.Query("KDEN", time.Time{}, time.Time{})
+	if err != nil || len(got) != 2 || got[0].Timestamp != "2026-02-17T12:53:00+00:00" {
+		t.Errorf("Query() after Add = %v, %v, want 2 observations", got, err)
+	}
+}
+
+func TestStations(t *testing.T) {
+	a, _ := Open(t.TempDir())
+	a.Add("KDEN", []nws.Observation{obs("2026-02-17T10:53:00+00:00", 1)})
+	a.Add("KBJC", []nws.Observation{obs("2026-02-17T10:53:00+00:00", 1)})
+	got, err := a.Stations()
+	if err != nil || len(got) != 2 || got[0] != "KBJC" || got[1] != "KDEN" {
+		t.Errorf("Stations() = %v, %v, want [KBJC KDEN]", got, err)
+	}
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir, err := DefaultDir()
+	if err != nil {
+		t.Fatalf("DefaultDir() error = %v", err)
+	}
+	if !strings.HasSuffix(dir, filepath.Join(".config", "lastwind", "archive")) {
+		t.Errorf("DefaultDir() = %q", dir)
+	}
+}
@@ -0,0 +1,62 @@
+package archive
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a duration like time.ParseDuration but also
+// accepts days ("30d") and weeks ("2w"), alone or combined ("1d12h").
+// The duration must be positive.
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	var total time.Duration
+	for _, unit := range []struct {
+		suffix string
+		d      time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		i := strings.Index(s, unit.suffix)
+		if i < 0 {
+			continue
+		}
+		n, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		total += time.Duration(n * float64(unit.d))
+		s = s[i+1:]
+	}
+	if s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		total += d
+	}
+	if total <= 0 {
+		return 0, fmt.Errorf("invalid duration %q (must be positive)", orig)
+	}
+	return total, nil
+}
+
+// ParseTime parses an absolute time (RFC 3339 or a local YYYY-MM-DD date)
+// or a time relative to now such as "-36h" or "-30d".
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if rel, ok := strings.CutPrefix(s, "-"); ok {
+		if d, err := ParseDuration(rel); err == nil {
+			return now.Add(-d), nil
+		}
+	}
+	if s == "now" {
+		return now, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want RFC 3339, YYYY-MM-DD or a relative time like -36h)", s)
+}
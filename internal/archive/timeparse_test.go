@@ -0,0 +1,58 @@
+package archive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"36h", 36 * time.Hour},
+		{"30d", 30 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1d12h", 36 * time.Hour},
+		{"1w2d", 9 * 24 * time.Hour},
+		{"90m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", tt.input, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "d", "xd", "30", "3x", "-3h", "0h", "0d", "-1d", "1d-36h"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) expected error", in)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2026-02-01T06:00:00Z", time.Date(2026, 2, 1, 6, 0, 0, 0, time.UTC)},
+		{"2026-02-01", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"-36h", now.Add(-36 * time.Hour)},
+		{"-30d", now.AddDate(0, 0, -30)},
+		{"now", now},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.input, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, %v, want %v", tt.input, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "yesterday", "36h", "-x"} {
+		if _, err := ParseTime(in, now); err == nil {
+			t.Errorf("ParseTime(%q) expected error", in)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Package aviation computes aviation weather categories from observations
+// and forecasts.
+package aviation
+
+import (
+	"math"
+
+	"lastwind/internal/metar"
+	"lastwind/internal/nws"
+	"lastwind/internal/term"
+)
+
+// FlightCategory is an FAA flight category. The empty category means the
+// observation lacked the visibility to decide.
+type FlightCategory string
+
+const (
+	VFR     FlightCategory = "VFR"
+	MVFR    FlightCategory = "MVFR"
+	IFR     FlightCategory = "IFR"
+	LIFR    FlightCategory = "LIFR"
+	Unknown FlightCategory = ""
+)
+
+// Categories lists the flight categories from best to worst.
+var Categories = []FlightCategory{VFR, MVFR, IFR, LIFR}
+
+// Category returns the flight category for a ceiling and visibility in
+// metres; a nil ceiling means there is none (no broken, overcast or
+// obscured layer). The lower of the two conditions decides:
+//
+//	LIFR  ceiling below 500 ft or visibility below 1 mi
+//	IFR   ceiling below 1,000 ft or visibility below 3 mi
+//	MVFR  ceiling up to 3,000 ft or visibility up to 5 mi
+//	VFR   otherwise
+//
+// Ceilings are rounded to the nearest 100 ft and visibility to a
+// hundredth of a mile first, since the API converts them from the
+// reported feet and statute miles.
+func Category(ceiling, visibility *float64) FlightCategory {
+	if visibility == nil {
+		return Unknown
+	}
+	miles := math.Round(*visibility/1609.344*100) / 100
+	feet := math.Inf(1)
+	if ceiling != nil {
+		feet = math.Round(*ceiling/0.3048/100) * 100
+	}
+
+	switch {
+	case feet < 500 || miles < 1:
+		return LIFR
+	case feet < 1000 || miles < 3:
+		return IFR
+	case feet <= 3000 || miles <= 5:
+		return MVFR
+	}
+	return VFR
+}
+
+// ObservationCategory returns the flight category of an observation,
+// ignoring a visibility the quality control policy excludes.
+func ObservationCategory(o nws.Observation, qc nws.QCPolicy) FlightCategory {
+	return Category(o.Ceiling(), qc.Value(o.Visibility))
+}
+
+// SegmentCategory returns the flight category forecast by a TAF segment.
+// CAVOK counts as VFR; a segment that doesn't forecast the visibility is
+// Unknown.
+func SegmentCategory(s metar.Segment) FlightCategory {
+	if s.CAVOK {
+		return VFR
+	}
+	if s.Visibility == nil {
+		return Unknown
+	}
+	return Category(s.Ceiling(), &s.Visibility.Distance)
+}
+
+// Rank orders categories from VFR (0) to LIFR (3); Unknown is -1.
+func (c FlightCategory) Rank() int {
+	for i, cat := range Categories {
+		if c == cat {
+			return i
+		}
+	}
+	return -1
+}
+
+// colors are the customary chart colours for each category.
+var colors = map[FlightCategory]string{
+	VFR:  term.Green,
+	MVFR: term.Blue,
+	IFR:  term.Red,
+	LIFR: term.Purple,
+}
+
+// Colored returns the category name in its chart colour, or "-" when it's
+// unknown.
+func (c FlightCategory) Colored() string {
+	if c == Unknown {
+		return "-"
+	}
+	return term.Color(string(c), term.Bold, colors[c])
+}
@@ -0,0 +1,85 @@
+package aviation
+
+import (
+	"testing"
+	"time"
+
+	"lastwind/internal/metar"
+	"lastwind/internal/nws"
+)
+
+func ptr(f float64) *float64 {
+	return &f
+}
+
+func feet(ft float64) *float64 {
+	return ptr(ft * 0.3048)
+}
+
+func miles(mi float64) *float64 {
+	return ptr(mi * 1609.344)
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		ceiling    *float64
+		visibility *float64
+		want       FlightCategory
+	}{
+		{"clear", nil, miles(10), VFR},
+		{"high ceiling", feet(3100), miles(6), VFR},
+		{"ceiling 3000", feet(3000), miles(10), MVFR},
+		{"visibility 5", nil, miles(5), MVFR},
+		{"API-rounded 5 mi", nil, ptr(8050), MVFR},
+		{"API-rounded 3000 ft", ptr(914), miles(10), MVFR},
+		{"ceiling 900", feet(900), miles(10), IFR},
+		{"visibility 2", feet(5000), miles(2), IFR},
+		{"visibility 3", nil, miles(3), MVFR},
+		{"ceiling 400", feet(400), miles(10), LIFR},
+		{"visibility 1/2", nil, miles(0.5), LIFR},
+		{"worse of the two", feet(800), miles(0.75), LIFR},
+		{"no visibility", feet(800), nil, Unknown},
+	}
+	for _, tt := range tests {
+		if got := Category(tt.ceiling, tt.visibility); got != tt.want {
+			t.Errorf("%s: Category = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestObservationCategory(t *testing.T) {
+	o := nws.Observation{
+		Visibility: nws.NullFloat64{Value: miles(10)},
+		CloudLayers: []nws.CloudLayer{
+			{Amount: "FEW", Base: nws.NullFloat64{Value: feet(300)}},
+			{Amount: "BKN", Base: nws.NullFloat64{Value: feet(800)}},
+		},
+	}
+	if got := ObservationCategory(o, nws.QCLenient); got != IFR {
+		t.Errorf("ObservationCategory = %q, want IFR", got)
+	}
+
+	o.Visibility.QualityControl = "X"
+	if got := ObservationCategory(o, nws.QCLenient); got != Unknown {
+		t.Errorf("ObservationCategory with rejected visibility = %q, want unknown", got)
+	}
+}
+
+func TestSegmentCategory(t *testing.T) {
+	taf, err := metar.ParseTAF("TAF KDEN 171720Z 1718/1824 27015KT P6SM BKN025 TEMPO 1720/1724 VRB20G35KT TSRA FM180200 30008KT CAVOK", time.Date(2024, 3, 17, 17, 20, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []FlightCategory{MVFR, Unknown, VFR} {
+		if got := SegmentCategory(taf.Segments[i]); got != want {
+			t.Errorf("SegmentCategory(%q) = %q, want %q", taf.Segments[i].Raw, got, want)
+		}
+	}
+}
+
+func TestFlightCategory_Rank(t *testing.T) {
+	if VFR.Rank() != 0 || LIFR.Rank() != 3 || Unknown.Rank() != -1 || IFR.Rank() <= MVFR.Rank() {
+		t.Errorf("ranks = %d %d %d %d %d", VFR.Rank(), MVFR.Rank(), IFR.Rank(), LIFR.Rank(), Unknown.Rank())
+	}
+}
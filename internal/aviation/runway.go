@@ -0,0 +1,226 @@
+package aviation
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"lastwind/internal/nws"
+	"lastwind/internal/units"
+)
+
+// DefaultCrosswindLimit is the crosswind component in km/h (15 kt) above
+// which an observation is flagged when no limit is configured.
+const DefaultCrosswindLimit = 15 * 1.852
+
+// Runway is one end of a runway: its designator, such as "30R", and the
+// heading in degrees true that aircraft fly along it.
+type Runway struct {
+	Designator string
+	Heading    float64
+}
+
+var runwayRe = regexp.MustCompile(`^(\d{1,2})([LCR]?)$`)
+
+// ParseRunway parses a runway such as "12L/30R", "12L" (its reciprocal
+// end is implied) or "12L/30R=117", and returns both ends. Without an
+// explicit heading each end's heading is its number times ten; since
+// runways are numbered by magnetic heading and the API reports wind
+// directions relative to true north, give the true heading of the first
+// end where the magnetic variation matters.
+func ParseRunway(spec string) ([]Runway, error) {
+	s := strings.ToUpper(strings.TrimSpace(spec))
+	var heading *float64
+	if i := strings.Index(s, "="); i >= 0 {
+		h, err := strconv.ParseFloat(strings.TrimSpace(s[i+1:]), 64)
+		if err != nil || h < 0 || h > 360 {
+			return nil, fmt.Errorf("invalid heading in runway %q", spec)
+		}
+		heading = &h
+		s = strings.TrimSpace(s[:i])
+	}
+
+	ends := strings.Split(s, "/")
+	if len(ends) > 2 {
+		return nil, fmt.Errorf("invalid runway %q (use e.g. 12L/30R)", spec)
+	}
+	first, n, err := parseDesignator(ends[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid runway %q: %w", spec, err)
+	}
+	second := reciprocal(ends[0], n)
+	if len(ends) == 2 {
+		if second, _, err = parseDesignator(ends[1]); err != nil {
+			return nil, fmt.Errorf("invalid runway %q: %w", spec, err)
+		}
+	}
+
+	h := float64(n * 10)
+	if heading != nil {
+		h = *heading
+	}
+	return []Runway{
+		{Designator: first, Heading: math.Mod(h, 360)},
+		{Designator: second, Heading: math.Mod(h+180, 360)},
+	}, nil
+}
+
+// ParseRunways parses a comma-separated list of runways, as accepted by
+// ParseRunway.
+func ParseRunways(specs []string) ([]Runway, error) {
+	var runways []Runway
+	for _, spec := range specs {
+		for _, s := range strings.Split(spec, ",") {
+			if strings.TrimSpace(s) == "" {
+				continue
+			}
+			ends, err := ParseRunway(s)
+			if err != nil {
+				return nil, err
+			}
+			runways = append(runways, ends...)
+		}
+	}
+	return runways, nil
+}
+
+func parseDesignator(s string) (string, int, error) {
+	m := runwayRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", 0, fmt.Errorf("bad designator %q", s)
+	}
+	n, _ := strconv.Atoi(m[1])
+	if n < 1 || n > 36 {
+		return "", 0, fmt.Errorf("runway number %d out of range 1-36", n)
+	}
+	return fmt.Sprintf("%02d%s", n, m[2]), n, nil
+}
+
+// reciprocal returns the designator of the opposite end of a runway.
+func reciprocal(designator string, n int) string {
+	r := (n+17)%36 + 1
+	side := map[string]string{"L": "R", "R": "L", "C": "C"}[designator[len(designator)-1:]]
+	return fmt.Sprintf("%02d%s", r, side)
+}
+
+// Components is a wind resolved along a runway. Headwind is negative for
+// a tailwind; Crosswind is positive from the right.
+type Components struct {
+	Headwind  float64
+	Crosswind float64
+}
+
+// WindComponents resolves a wind blowing from direction (degrees) at speed
+// along a runway heading. The components are in the unit of speed.
+func WindComponents(heading, direction, speed float64) Components {
+	angle := (direction - heading) * math.Pi / 180
+	return Components{Headwind: speed * math.Cos(angle), Crosswind: speed * math.Sin(angle)}
+}
+
+// BestRunway returns the runway end most nearly into a wind from
+// direction, which has the greatest headwind component. It returns false
+// when there are no runways.
+func BestRunway(runways []Runway, direction float64) (Runway, bool) {
+	best, ok := Runway{}, false
+	for _, r := range runways {
+		if !ok || WindComponents(r.Heading, direction, 1).Headwind > WindComponents(best.Heading, direction, 1).Headwind {
+			best, ok = r, true
+		}
+	}
+	return best, ok
+}
+
+// RunwayWind is an observed wind resolved along the best runway for it,
+// in km/h. Gust is nil when no gust was reported.
+type RunwayWind struct {
+	Runway    Runway
+	Sustained Components
+	Gust      *Components
+}
+
+// ObservationRunwayWind resolves an observation's wind along the best of
+// runways, ignoring values the quality control policy excludes. It
+// returns false when there are no runways or the observation has no wind
+// direction and speed (calm or variable winds).
+func ObservationRunwayWind(runways []Runway, o nws.Observation, qc nws.QCPolicy) (RunwayWind, bool) {
+	dir, speed := qc.Value(o.WindDirection), qc.Value(o.WindSpeed)
+	if dir == nil || speed == nil || *speed == 0 {
+		return RunwayWind{}, false
+	}
+	r, ok := BestRunway(runways, *dir)
+	if !ok {
+		return RunwayWind{}, false
+	}
+	w := RunwayWind{Runway: r, Sustained: WindComponents(r.Heading, *dir, *speed)}
+	if g := qc.Value(o.WindGust); g != nil && *g > 0 {
+		c := WindComponents(r.Heading, *dir, *g)
+		w.Gust = &c
+	}
+	return w, true
+}
+
+// MaxCrosswind returns the larger of the sustained and gust crosswind
+// components, ignoring their side.
+func (w RunwayWind) MaxCrosswind() float64 {
+	x := math.Abs(w.Sustained.Crosswind)
+	if w.Gust != nil {
+		x = math.Max(x, math.Abs(w.Gust.Crosswind))
+	}
+	return x
+}
+
+// Exceeds reports whether the crosswind is above limit (km/h).
+func (w RunwayWind) Exceeds(limit float64) bool {
+	return limit > 0 && w.MaxCrosswind() > limit
+}
+
+// Format gives the components compactly for a table cell, e.g.
+// "30R H17 X8R" or, with gusts, "30R H17/25 X8/12R". A tailwind is shown
+// as T, and the crosswind side as L or R.
+func (w RunwayWind) Format(u units.Speed) string {
+	along := func(c Components) float64 { return math.Round(u.Convert(c.Headwind)) }
+	across := func(c Components) float64 { return math.Round(math.Abs(u.Convert(c.Crosswind))) }
+
+	h, x := along(w.Sustained), across(w.Sustained)
+	head := fmt.Sprintf("H%.0f", h)
+	if h < 0 {
+		head = fmt.Sprintf("T%.0f", -h)
+	}
+	cross := fmt.Sprintf("X%.0f", x)
+	if w.Gust != nil {
+		gh := along(*w.Gust)
+		head += fmt.Sprintf("/%.0f", math.Abs(gh))
+		cross += fmt.Sprintf("/%.0f", across(*w.Gust))
+	}
+	if x > 0 || (w.Gust != nil && across(*w.Gust) > 0) {
+		cross += side(w.Sustained.Crosswind)
+	}
+	return w.Runway.Designator + " " + head + " " + cross
+}
+
+// Describe gives the components in words, e.g. "30R, headwind 17 mph,
+// crosswind 8 mph from the right, gusting 25 and 12 mph".
+func (w RunwayWind) Describe(u units.Speed) string {
+	speed := func(v float64) string { return u.Format(math.Abs(v)) + " " + u.Label() }
+	head := "headwind "
+	if w.Sustained.Headwind < 0 {
+		head = "tailwind "
+	}
+	s := w.Runway.Designator + ", " + head + speed(w.Sustained.Headwind) + ", crosswind " + speed(w.Sustained.Crosswind)
+	if math.Round(u.Convert(math.Abs(w.Sustained.Crosswind))) > 0 {
+		s += " from the " + map[string]string{"L": "left", "R": "right"}[side(w.Sustained.Crosswind)]
+	}
+	if w.Gust != nil {
+		s += fmt.Sprintf(", gusting %s and %s", u.Format(math.Abs(w.Gust.Headwind)), speed(w.Gust.Crosswind))
+	}
+	return s
+}
+
+func side(crosswind float64) string {
+	if crosswind < 0 {
+		return "L"
+	}
+	return "R"
+}
@@ -0,0 +1,125 @@
+package aviation
+
+import (
+	"math"
+	"testing"
+
+	"lastwind/internal/nws"
+	"lastwind/internal/units"
+)
+
+func TestParseRunway(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []Runway
+	}{
+		{"12L/30R", []Runway{{"12L", 120}, {"30R", 300}}},
+		{"3/21", []Runway{{"03", 30}, {"21", 210}}},
+		{"17c", []Runway{{"17C", 170}, {"35C", 350}}},
+		{"36", []Runway{{"36", 0}, {"18", 180}}},
+		{"12L/30R=117", []Runway{{"12L", 117}, {"30R", 297}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRunway(tt.spec)
+		if err != nil {
+			t.Errorf("ParseRunway(%q): %v", tt.spec, err)
+			continue
+		}
+		if len(got) != 2 || got[0] != tt.want[0] || got[1] != tt.want[1] {
+			t.Errorf("ParseRunway(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "37", "12X", "12/30/18", "12=400", "RW12"} {
+		if _, err := ParseRunway(spec); err == nil {
+			t.Errorf("ParseRunway(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestParseRunways(t *testing.T) {
+	got, err := ParseRunways([]string{"03/21, 12L/30R", "12R/30L"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 6 || got[2].Designator != "12L" || got[5].Designator != "30L" {
+		t.Errorf("ParseRunways = %v", got)
+	}
+}
+
+func TestWindComponents(t *testing.T) {
+	tests := []struct {
+		heading, direction, speed float64
+		head, cross               float64
+	}{
+		{300, 300, 20, 20, 0},
+		{300, 120, 20, -20, 0},
+		{300, 330, 20, 17.32, 10},
+		{300, 270, 20, 17.32, -10},
+		{90, 0, 20, 0, -20},
+	}
+	for _, tt := range tests {
+		c := WindComponents(tt.heading, tt.direction, tt.speed)
+		if math.Abs(c.Headwind-tt.head) > 0.01 || math.Abs(c.Crosswind-tt.cross) > 0.01 {
+			t.Errorf("WindComponents(%v, %v, %v) = %+v, want head %v cross %v", tt.heading, tt.direction, tt.speed, c, tt.head, tt.cross)
+		}
+	}
+}
+
+func TestBestRunway(t *testing.T) {
+	runways, _ := ParseRunways([]string{"03/21,12L/30R"})
+	tests := []struct {
+		direction float64
+		want      string
+	}{
+		{280, "30R"},
+		{200, "21"},
+		{40, "03"},
+		{130, "12L"},
+	}
+	for _, tt := range tests {
+		r, ok := BestRunway(runways, tt.direction)
+		if !ok || r.Designator != tt.want {
+			t.Errorf("BestRunway(%v) = %v, want %s", tt.direction, r, tt.want)
+		}
+	}
+	if _, ok := BestRunway(nil, 270); ok {
+		t.Error("BestRunway(nil) should report no runway")
+	}
+}
+
+func TestObservationRunwayWind(t *testing.T) {
+	runways, _ := ParseRunways([]string{"12L/30R"})
+	knots := func(kt float64) nws.NullFloat64 { return nws.NullFloat64{Value: ptr(kt * 1.852)} }
+	o := nws.Observation{
+		WindDirection: nws.NullFloat64{Value: ptr(330)},
+		WindSpeed:     knots(20),
+		WindGust:      knots(30),
+	}
+
+	w, ok := ObservationRunwayWind(runways, o, nws.QCLenient)
+	if !ok || w.Runway.Designator != "30R" || w.Gust == nil {
+		t.Fatalf("ObservationRunwayWind = %+v, %v", w, ok)
+	}
+	if got := w.Format(units.Knots); got != "30R H17/26 X10/15R" {
+		t.Errorf("Format = %q", got)
+	}
+	if got := w.Describe(units.Knots); got != "30R, headwind 17 kt, crosswind 10 kt from the right, gusting 26 and 15 kt" {
+		t.Errorf("Describe = %q", got)
+	}
+	if !w.Exceeds(14*1.852) || w.Exceeds(16*1.852) {
+		t.Errorf("Exceeds: max crosswind %.1f km/h", w.MaxCrosswind())
+	}
+
+	o.WindGust = nws.NullFloat64{}
+	o.WindDirection = nws.NullFloat64{Value: ptr(90)}
+	w, _ = ObservationRunwayWind(runways, o, nws.QCLenient)
+	if got := w.Format(units.Knots); got != "12L H17 X10L" {
+		t.Errorf("Format = %q", got)
+	}
+
+	o.WindSpeed = knots(0)
+	if _, ok := ObservationRunwayWind(runways, o, nws.QCLenient); ok {
+		t.Error("calm wind should have no components")
+	}
+}
@@ -0,0 +1,101 @@
+// Package cli holds helpers shared by the lastwind commands.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"lastwind/internal/httpcache"
+	"lastwind/internal/nws"
+)
+
+// Exit codes returned by every command.
+const (
+	ExitOK               = 0
+	ExitError            = 1
+	ExitUsage            = 2
+	ExitNotFound         = 3
+	ExitUnsupportedPoint = 4
+	ExitRateLimited      = 5
+	ExitUpstream         = 6
+	ExitNetwork          = 7
+)
+
+// ExitCode maps an error to the command exit code for its kind.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, nws.ErrUnsupportedPoint):
+		return ExitUnsupportedPoint
+	case errors.Is(err, nws.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, nws.ErrRateLimited):
+		return ExitRateLimited
+	case errors.Is(err, nws.ErrUpstream):
+		return ExitUpstream
+	case isNetwork(err):
+		return ExitNetwork
+	}
+	return ExitError
+}
+
+// Message returns a short, human-friendly explanation of err.
+func Message(err error) string {
+	var notCached *httpcache.ErrNotCached
+	if errors.As(err, &notCached) {
+		return "no cached data for this request; run once without -offline first"
+	}
+
+	var apiErr *nws.APIError
+	if !errors.As(err, &apiErr) {
+		if isNetwork(err) {
+			return fmt.Sprintf("could not reach the NWS API (%v)", err)
+		}
+		return err.Error()
+	}
+
+	detail := apiErr.Detail
+	if detail == "" {
+		detail = apiErr.Title
+	}
+	if detail == "" {
+		detail = apiErr.Body
+	}
+
+	var msg string
+	switch apiErr.Kind() {
+	case nws.ErrUnsupportedPoint:
+		msg = "the NWS API has no data for this location; only US states and territories are covered"
+	case nws.ErrNotFound:
+		msg = "not found"
+		if detail != "" {
+			msg += ": " + detail
+		}
+	case nws.ErrRateLimited:
+		msg = "the NWS API is rate limiting requests; wait a minute and try again"
+	case nws.ErrUpstream:
+		msg = fmt.Sprintf("the NWS API is having problems (HTTP %d); try again later", apiErr.StatusCode)
+	default:
+		msg = apiErr.Error()
+	}
+	if apiErr.CorrelationID != "" && apiErr.Kind() == nws.ErrUpstream {
+		msg += fmt.Sprintf(" [correlation ID %s]", apiErr.CorrelationID)
+	}
+	return msg
+}
+
+// Fatal prints "Error <doing>: <message>" to stderr and exits with the
+// code for err.
+func Fatal(doing string, err error) {
+	fmt.Fprintf(os.Stderr, "Error %s: %s\n", doing, Message(err))
+	os.Exit(ExitCode(err))
+}
+
+func isNetwork(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"lastwind/internal/httpcache"
+	"lastwind/internal/nws"
+)
+
+func TestExitCode(t *testing.T) {
+	_, netErr := http.Get("http://localhost:1")
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"plain", errors.New("boom"), ExitError},
+		{"not found", &nws.APIError{StatusCode: 404}, ExitNotFound},
+		{"invalid point", &nws.APIError{StatusCode: 404, Type: "https://api.weather.gov/problems/InvalidPoint"}, ExitUnsupportedPoint},
+		{"rate limited", &nws.APIError{StatusCode: 429}, ExitRateLimited},
+		{"upstream", &nws.APIError{StatusCode: 503}, ExitUpstream},
+		{"wrapped", fmt.Errorf("fetching: %w", &nws.APIError{StatusCode: 500}), ExitUpstream},
+		{"bad request", &nws.APIError{StatusCode: 400}, ExitError},
+		{"network", netErr, ExitNetwork},
+		{"timeout", context.DeadlineExceeded, ExitNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"plain", errors.New("boom"), "boom"},
+		{"not found", &nws.APIError{StatusCode: 404, Title: "Not Found", Detail: "Station KXYZ not found"}, "not found: Station KXYZ not found"},
+		{"invalid point", &nws.APIError{StatusCode: 404, Type: "https://api.weather.gov/problems/InvalidPoint"}, "only US states and territories"},
+		{"rate limited", &nws.APIError{StatusCode: 429}, "rate limiting"},
+		{"upstream", &nws.APIError{StatusCode: 503, CorrelationID: "abc123"}, "(HTTP 503); try again later [correlation ID abc123]"},
+		{"bad request", &nws.APIError{StatusCode: 400, Title: "Bad Request"}, "HTTP 400: Bad Request"},
+		{"timeout", context.DeadlineExceeded, "could not reach the NWS API"},
+		{"not cached", fmt.Errorf("get: %w", &httpcache.ErrNotCached{URL: "x"}), "run once without -offline"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Message(tt.err); !strings.Contains(got, tt.want) {
+				t.Errorf("Message() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
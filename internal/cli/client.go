@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"lastwind/internal/config"
+	"lastwind/internal/httpcache"
+	"lastwind/internal/nws"
+)
+
+// NewClient returns an NWS client whose responses are cached under
+// config.CacheDir(). In offline mode only cached responses are used. The
+// returned transport is nil if the cache directory can't be determined.
+func NewClient(offline bool) (*nws.Client, *httpcache.Transport) {
+	client := nws.NewClient()
+	dir, err := config.CacheDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: response cache disabled: %v\n", err)
+		return client, nil
+	}
+	cache := httpcache.New(dir, offline)
+	client.HTTPClient = &http.Client{Timeout: client.HTTPClient.Timeout, Transport: cache}
+	return client, cache
+}
+
+// StaleNotice prints a notice to stderr when any response was served
+// from the cache past its expiry, because the network was unavailable or
+// -offline was given.
+func StaleNotice(cache *httpcache.Transport) {
+	if cache == nil {
+		return
+	}
+	if since, ok := cache.StaleSince(); ok {
+		reason := "network unavailable"
+		if cache.Offline {
+			reason = "offline"
+		}
+		fmt.Fprintf(os.Stderr, "Note: %s, showing cached data (stale as of %s)\n",
+			reason, since.Local().Format("Jan 02 15:04"))
+	}
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"lastwind/internal/config"
+	"lastwind/internal/gazetteer"
+)
+
+// UseLocation resolves the -loc flag against the config and copies the
+// location's coordinates and station into any -lat, -lon and -station
+// flags that weren't given explicitly. It exits on an unknown name.
+func UseLocation(cfg config.Config, name string) config.Location {
+	loc, err := cfg.Location(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitUsage)
+	}
+	applyLocation(flag.CommandLine, loc)
+	return loc
+}
+
+func applyLocation(fs *flag.FlagSet, loc config.Location) {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	values := map[string]string{
+		"lat":     strconv.FormatFloat(loc.Latitude, 'f', -1, 64),
+		"lon":     strconv.FormatFloat(loc.Longitude, 'f', -1, 64),
+		"station": loc.Station,
+	}
+	for name, v := range values {
+		if fs.Lookup(name) != nil && !set[name] {
+			fs.Set(name, v)
+		}
+	}
+}
+
+// Given reports whether any of the named flags was set on the command
+// line. Call it before UsePlace and UseLocation, which set -lat, -lon and
+// -station themselves.
+func Given(names ...string) bool {
+	return given(flag.CommandLine, names...)
+}
+
+func given(fs *flag.FlagSet, names ...string) bool {
+	found := false
+	fs.Visit(func(f *flag.Flag) {
+		for _, n := range names {
+			found = found || f.Name == n
+		}
+	})
+	return found
+}
+
+// PointStation picks the station for a point: explicit when given, else
+// the configured location's station unless -lat, -lon, -place or -zip
+// chose another point (pointGiven), else nearest, the station nearest the
+// point.
+func PointStation(explicit, nearest string, loc config.Location, pointGiven bool) string {
+	switch {
+	case explicit != "":
+		return strings.ToUpper(explicit)
+	case loc.Station != "" && !pointGiven:
+		return strings.ToUpper(loc.Station)
+	}
+	return nearest
+}
+
+// UsePlace geocodes the -place or -zip flag with the offline gazetteer and
+// sets any -lat and -lon flags to the result. Call it before UseLocation. It
+// returns false when neither flag is given, and exits when the place is
+// unknown or ambiguous, listing the candidates.
+func UsePlace(place, zip string) (gazetteer.Place, bool) {
+	p, ok, err := applyPlace(flag.CommandLine, place, zip)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var amb *gazetteer.AmbiguousError
+		if errors.As(err, &amb) {
+			fmt.Fprintf(os.Stderr, "Add the state to choose one, e.g. -place %q.\n", amb.Matches[0].String())
+		}
+		os.Exit(ExitUsage)
+	}
+	return p, ok
+}
+
+func applyPlace(fs *flag.FlagSet, place, zip string) (gazetteer.Place, bool, error) {
+	if place == "" && zip == "" {
+		return gazetteer.Place{}, false, nil
+	}
+	if place != "" && zip != "" {
+		return gazetteer.Place{}, false, fmt.Errorf("-place and -zip cannot be combined")
+	}
+	var conflict bool
+	fs.Visit(func(f *flag.Flag) { conflict = conflict || f.Name == "lat" || f.Name == "lon" })
+	if conflict {
+		return gazetteer.Place{}, false, fmt.Errorf("-place and -zip cannot be combined with -lat or -lon")
+	}
+
+	var p gazetteer.Place
+	var err error
+	if zip != "" {
+		p, err = gazetteer.LookupZIP(zip)
+	} else {
+		p, err = gazetteer.Lookup(place)
+	}
+	if err != nil {
+		return p, false, err
+	}
+	fs.Set("lat", strconv.FormatFloat(p.Latitude, 'f', -1, 64))
+	fs.Set("lon", strconv.FormatFloat(p.Longitude, 'f', -1, 64))
+	return p, true, nil
+}
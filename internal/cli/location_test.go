@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"flag"
+	"testing"
+
+	"lastwind/internal/config"
+)
+
+func TestApplyLocation(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	lat := fs.Float64("lat", 1, "")
+	lon := fs.Float64("lon", 2, "")
+	station := fs.String("station", "KEIK", "")
+	if err := fs.Parse([]string{"-lon", "-105.5"}); err != nil {
+		t.Fatal(err)
+	}
+
+	applyLocation(fs, config.Location{Station: "KDEN", Latitude: 39.8561, Longitude: -104.6737})
+
+	if *lat != 39.8561 {
+		t.Errorf("lat = %v, want 39.8561", *lat)
+	}
+	if *lon != -105.5 {
+		t.Errorf("lon = %v, explicit flag should be kept", *lon)
+	}
+	if *station != "KDEN" {
+		t.Errorf("station = %q, want KDEN", *station)
+	}
+}
+
+func TestApplyLocation_MissingFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	lat := fs.Float64("lat", 1, "")
+	fs.Parse(nil)
+
+	applyLocation(fs, config.Location{Station: "KDEN", Latitude: 39.8561})
+	if *lat != 39.8561 {
+		t.Errorf("lat = %v, want 39.8561", *lat)
+	}
+}
+
+func TestApplyPlace(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	lat := fs.Float64("lat", 1, "")
+	lon := fs.Float64("lon", 2, "")
+	fs.Parse(nil)
+
+	p, ok, err := applyPlace(fs, "boulder, co", "")
+	if err != nil || !ok {
+		t.Fatalf("applyPlace() = %v, %v, %v", p, ok, err)
+	}
+	if *lat != p.Latitude || *lon != p.Longitude || p.String() != "Boulder, CO" {
+		t.Errorf("lat, lon = %v, %v for %s", *lat, *lon, p)
+	}
+
+	// The coordinates applied above now count as set, like an explicit -lat.
+	if _, _, err := applyPlace(fs, "", "80301"); err == nil {
+		t.Error("applyPlace() expected error when -lat is set")
+	}
+}
+
+func TestApplyPlace_Errors(t *testing.T) {
+	tests := []struct {
+		name, place, zip string
+	}{
+		{"both", "Boulder", "80301"},
+		{"unknown", "Gotham", ""},
+		{"ambiguous", "Springfield", ""},
+		{"bad zip", "", "8030"},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Float64("lat", 1, "")
+		fs.Float64("lon", 2, "")
+		fs.Parse(nil)
+		if _, _, err := applyPlace(fs, tt.place, tt.zip); err == nil {
+			t.Errorf("%s: applyPlace() expected error", tt.name)
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if _, ok, err := applyPlace(fs, "", ""); ok || err != nil {
+		t.Errorf("applyPlace() with no place = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestGiven(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Float64("lat", 1, "")
+	fs.Float64("lon", 2, "")
+	fs.String("place", "", "")
+	fs.String("zip", "", "")
+	fs.String("station", "", "")
+	fs.Bool("taf", false, "")
+	if err := fs.Parse([]string{"-place", "Boulder, CO", "-taf"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !given(fs, "lat", "lon", "place", "zip") {
+		t.Error("given() should report -place")
+	}
+	if given(fs, "station") {
+		t.Error("given(station) before applyLocation should be false")
+	}
+}
+
+func TestPointStation_PlaceWithTAF(t *testing.T) {
+	// forecast -place "Boulder, CO" -taf: the configured station mustn't
+	// stand in for the geocoded point.
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Float64("lat", 1, "")
+	fs.Float64("lon", 2, "")
+	place := fs.String("place", "", "")
+	fs.String("zip", "", "")
+	fs.Bool("taf", false, "")
+	tafStation := fs.String("taf-station", "", "")
+	if err := fs.Parse([]string{"-place", "Boulder, CO", "-taf"}); err != nil {
+		t.Fatal(err)
+	}
+	pointGiven := given(fs, "lat", "lon", "place", "zip")
+	if _, _, err := applyPlace(fs, *place, ""); err != nil {
+		t.Fatal(err)
+	}
+	home := config.Location{Station: "KEIK", Latitude: 40.01, Longitude: -105.05}
+	applyLocation(fs, home)
+
+	if got := PointStation(*tafStation, "KBDU", home, pointGiven); got != "KBDU" {
+		t.Errorf("PointStation() = %q, want the station nearest the place", got)
+	}
+}
+
+func TestPointStation(t *testing.T) {
+	home := config.Location{Station: "keik"}
+	tests := []struct {
+		explicit   string
+		loc        config.Location
+		pointGiven bool
+		want       string
+	}{
+		{"", home, false, "KEIK"},
+		{"", home, true, "KDEN"},
+		{"kbjc", home, true, "KBJC"},
+		{"", config.Location{}, false, "KDEN"},
+	}
+	for _, tt := range tests {
+		if got := PointStation(tt.explicit, "KDEN", tt.loc, tt.pointGiven); got != tt.want {
+			t.Errorf("PointStation(%q, %+v, %v) = %q, want %q", tt.explicit, tt.loc, tt.pointGiven, got, tt.want)
+		}
+	}
+}
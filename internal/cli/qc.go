@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"lastwind/internal/config"
+	"lastwind/internal/nws"
+)
+
+// QCPolicy returns the policy named by the -qc flag, or the one in the
+// config file when the flag is empty. It exits on an invalid name.
+func QCPolicy(cfg config.Config, name string) nws.QCPolicy {
+	p, err := cfg.QCPolicy()
+	if name != "" {
+		p, err = nws.ParseQCPolicy(name)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitUsage)
+	}
+	return p
+}
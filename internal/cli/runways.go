@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"lastwind/internal/aviation"
+	"lastwind/internal/config"
+	"lastwind/internal/units"
+)
+
+// Runways returns the runways given by the -runways flag, or those
+// configured for station when the flag is empty. It exits on an invalid
+// runway.
+func Runways(cfg config.Config, station, spec string) []aviation.Runway {
+	runways, err := cfg.StationRunways(station)
+	if spec != "" {
+		runways, err = aviation.ParseRunways([]string{spec})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitUsage)
+	}
+	return runways
+}
+
+// CrosswindLimit returns the -crosswind-limit flag, given in the display
+// speed unit, in km/h, or the configured limit when the flag is zero.
+func CrosswindLimit(cfg config.Config, limit float64, sys units.System) float64 {
+	if limit > 0 {
+		return limit / sys.Speed.Convert(1)
+	}
+	return cfg.CrosswindLimitKmh()
+}
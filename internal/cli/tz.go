@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	// Embed the zone database so station zones resolve on hosts without one.
+	_ "time/tzdata"
+)
+
+// ParseTimeZone resolves a -tz value: "local", "utc", "station" (the
+// default when empty) or an IANA zone name such as "America/Denver".
+// stationZone is the IANA name from the station or point metadata; when
+// it's missing or unknown, station times fall back to local time.
+func ParseTimeZone(spec, stationZone string) (*time.Location, error) {
+	switch strings.ToLower(spec) {
+	case "local":
+		return time.Local, nil
+	case "utc", "z":
+		return time.UTC, nil
+	case "", "station":
+		if stationZone == "" {
+			return time.Local, nil
+		}
+		loc, err := time.LoadLocation(stationZone)
+		if err != nil {
+			return time.Local, nil
+		}
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(spec)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q (use local, utc, station or an IANA name like America/Denver)", spec)
+	}
+	return loc, nil
+}
+
+// TimeZone is ParseTimeZone for the -tz flag; it exits on an invalid
+// zone.
+func TimeZone(spec, stationZone string) *time.Location {
+	loc, err := ParseTimeZone(spec, stationZone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitUsage)
+	}
+	return loc
+}
+
+// ZoneAbbrev returns the abbreviations loc uses at times, e.g. "MST", for
+// labelling them in headers. Times either side of a daylight saving change
+// give both, e.g. "MST/MDT". Zero times are ignored.
+func ZoneAbbrev(loc *time.Location, times ...time.Time) string {
+	var abbrevs []string
+	seen := make(map[string]bool)
+	for _, t := range times {
+		if t.IsZero() {
+			continue
+		}
+		if a := t.In(loc).Format("MST"); !seen[a] {
+			seen[a] = true
+			abbrevs = append(abbrevs, a)
+		}
+	}
+	return strings.Join(abbrevs, "/")
+}
+
+// TimestampZone is ZoneAbbrev for RFC 3339 timestamps as the API returns
+// them, skipping any that don't parse.
+func TimestampZone(loc *time.Location, timestamps ...string) string {
+	times := make([]time.Time, 0, len(timestamps))
+	for _, ts := range timestamps {
+		if t, err := time.Parse(time.RFC3339, ts); err == nil {
+			times = append(times, t)
+		}
+	}
+	return ZoneAbbrev(loc, times...)
+}
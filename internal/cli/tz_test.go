@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeZone(t *testing.T) {
+	tests := []struct {
+		spec, station string
+		want          string
+		wantErr       bool
+	}{
+		{"", "America/Denver", "America/Denver", false},
+		{"station", "America/Chicago", "America/Chicago", false},
+		{"", "", "Local", false},
+		{"station", "Mars/Olympus_Mons", "Local", false},
+		{"local", "America/Denver", "Local", false},
+		{"UTC", "America/Denver", "UTC", false},
+		{"Pacific/Honolulu", "America/Denver", "Pacific/Honolulu", false},
+		{"Nowhere/Special", "America/Denver", "", true},
+	}
+	for _, tt := range tests {
+		loc, err := ParseTimeZone(tt.spec, tt.station)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTimeZone(%q, %q) error = %v, wantErr %v", tt.spec, tt.station, err, tt.wantErr)
+			continue
+		}
+		if err == nil && loc.String() != tt.want {
+			t.Errorf("ParseTimeZone(%q, %q) = %s, want %s", tt.spec, tt.station, loc, tt.want)
+		}
+	}
+}
+
+func TestZoneAbbrev(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	winter := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2026, 7, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		loc   *time.Location
+		times []time.Time
+		want  string
+	}{
+		{time.UTC, []time.Time{summer}, "UTC"},
+		{denver, []time.Time{winter}, "MST"},
+		{denver, []time.Time{summer}, "MDT"},
+		{denver, []time.Time{winter, summer, winter}, "MST/MDT"},
+		{denver, []time.Time{{}, summer}, "MDT"},
+		{denver, nil, ""},
+	}
+	for _, tt := range tests {
+		if got := ZoneAbbrev(tt.loc, tt.times...); got != tt.want {
+			t.Errorf("ZoneAbbrev(%s, %v) = %q, want %q", tt.loc, tt.times, got, tt.want)
+		}
+	}
+}
+
+func TestTimestampZone(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := TimestampZone(denver, "2026-03-08T08:00:00+00:00", "garbage", "2026-03-08T10:00:00+00:00")
+	if got != "MST/MDT" {
+		t.Errorf("TimestampZone() = %q, want MST/MDT", got)
+	}
+}
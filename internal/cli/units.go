@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"lastwind/internal/config"
+	"lastwind/internal/units"
+)
+
+// Units returns the unit system named by the -units flag, or the one in
+// the config file when the flag is empty. It exits on an invalid spec.
+func Units(cfg config.Config, spec string) units.System {
+	sys, err := cfg.UnitSystem()
+	if spec != "" {
+		sys, err = units.Parse(spec)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitUsage)
+	}
+	return sys
+}
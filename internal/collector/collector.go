@@ -0,0 +1,117 @@
+// Package collector polls stations for their latest observation on a
+// schedule and stores new observations as they appear.
+package collector
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"lastwind/internal/nws"
+)
+
+// FetchFunc returns a station's latest observation.
+type FetchFunc func(ctx context.Context, station string) (nws.Observation, error)
+
+// Store persists observations, ignoring ones it already has.
+type Store interface {
+	Add(station string, observations []nws.Observation) (int, error)
+}
+
+// DefaultMaxBackoff is the longest wait between polls when MaxBackoff
+// isn't set.
+const DefaultMaxBackoff = time.Hour
+
+// Collector polls each station every Interval. After consecutive
+// failures a station's polling interval doubles, up to MaxBackoff (or
+// DefaultMaxBackoff if it's zero).
+type Collector struct {
+	Stations   []string
+	Interval   time.Duration
+	MaxBackoff time.Duration
+	Fetch      FetchFunc
+	Store      Store
+	Logger     *log.Logger
+}
+
+// Run polls until ctx is cancelled. Each station is polled immediately
+// and then on its own schedule.
+func (c *Collector) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, s := range c.Stations {
+		wg.Add(1)
+		go func(station string) {
+			defer wg.Done()
+			c.poll(ctx, station)
+		}(s)
+	}
+	wg.Wait()
+}
+
+func (c *Collector) poll(ctx context.Context, station string) {
+	failures := 0
+	for {
+		if err := c.collect(ctx, station); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			failures++
+		} else {
+			failures = 0
+		}
+
+		wait := c.Backoff(failures)
+		if failures > 0 {
+			c.logf("%s: retrying in %s", station, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Collector) collect(ctx context.Context, station string) error {
+	obs, err := c.Fetch(ctx, station)
+	if err != nil {
+		c.logf("%s: error fetching observation: %v", station, err)
+		return err
+	}
+	n, err := c.Store.Add(station, []nws.Observation{obs})
+	if err != nil {
+		c.logf("%s: error storing observation: %v", station, err)
+		return err
+	}
+	if n > 0 {
+		c.logf("%s: stored observation %s", station, obs.Timestamp)
+	}
+	return nil
+}
+
+// Backoff returns how long to wait before the next poll after the given
+// number of consecutive failures.
+func (c *Collector) Backoff(failures int) time.Duration {
+	limit := c.MaxBackoff
+	if limit <= 0 {
+		limit = DefaultMaxBackoff
+	}
+	wait := c.Interval
+	for i := 0; i < failures; i++ {
+		wait *= 2
+		if wait >= limit {
+			return max(limit, c.Interval)
+		}
+	}
+	return wait
+}
+
+func (c *Collector) logf(format string, args ...any) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, args...)
+	}
+}
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"lastwind/internal/nws"
+)
+
+type memStore struct {
+	mu   sync.Mutex
+	seen map[string]bool
+	adds int
+}
+
+func (s *memStore) Add(station string, observations []nws.Observation) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.adds++
+	n := 0
+	for _, o := range observations {
+		key := station + o.Timestamp
+		if !s.seen[key] {
+			s.seen[key] = true
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestCollector_Run(t *testing.T) {
+	store := &memStore{seen: map[string]bool{}}
+	var mu sync.Mutex
+	calls := map[string]int{}
+
+	var buf bytes.Buffer
+	c := &Collector{
+		Stations: []string{"KDEN", "KBJC"},
+		Interval: 5 * time.Millisecond,
+		Fetch: func(ctx context.Context, station string) (nws.Observation, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[station]++
+			if station == "KBJC" {
+				return nws.Observation{}, errors.New("HTTP 503")
+			}
+			// A new observation every other poll
+			return nws.Observation{Timestamp: string(rune('a' + calls[station]/2))}, nil
+		},
+		Store:  store,
+		Logger: log.New(&buf, "", 0),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+	c.Run(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["KDEN"] < 3 {
+		t.Errorf("KDEN polled %d times, want at least 3", calls["KDEN"])
+	}
+	// KBJC backs off: 5, 10, 20, 40ms so at most 4 polls in 60ms
+	if calls["KBJC"] < 1 || calls["KBJC"] > 4 {
+		t.Errorf("KBJC polled %d times, want 1-4 with backoff", calls["KBJC"])
+	}
+	if calls["KBJC"] >= calls["KDEN"] {
+		t.Errorf("failing station polled as often as healthy one (%d vs %d)", calls["KBJC"], calls["KDEN"])
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "KDEN: stored observation") {
+		t.Errorf("log missing stored observation:\n%s", out)
+	}
+	if !strings.Contains(out, "KBJC: error fetching observation: HTTP 503") {
+		t.Errorf("log missing fetch error:\n%s", out)
+	}
+}
+
+func TestCollector_Backoff(t *testing.T) {
+	c := &Collector{Interval: time.Minute, MaxBackoff: 10 * time.Minute}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, 2 * time.Minute},
+		{3, 8 * time.Minute},
+		{4, 10 * time.Minute},
+		{20, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := c.Backoff(tt.failures); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+
+	// Without a limit, doubling stops at DefaultMaxBackoff instead of
+	// overflowing.
+	c.MaxBackoff = 0
+	if got := c.Backoff(100); got != DefaultMaxBackoff {
+		t.Errorf("Backoff(100) without MaxBackoff = %v, want %v", got, DefaultMaxBackoff)
+	}
+}
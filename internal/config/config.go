@@ -2,7 +2,9 @@ package config
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,20 +12,184 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"lastwind/internal/aviation"
+	"lastwind/internal/gazetteer"
+	"lastwind/internal/geo"
+	"lastwind/internal/nws"
+	"lastwind/internal/units"
 )
 
+// Config is the contents of config.json: a list of named locations, which
+// one to use when none is given, the display unit system, the quality
+// control policy for observations, and the runways of stations whose
+// crosswinds should be checked.
 type Config struct {
+	Default        string              `json:"default"`
+	Units          string              `json:"units,omitempty"`
+	UnitOverrides  map[string]string   `json:"unit_overrides,omitempty"`
+	QC             string              `json:"qc,omitempty"`
+	Runways        map[string][]string `json:"runways,omitempty"`
+	CrosswindLimit float64             `json:"crosswind_limit_kt,omitempty"`
+	Locations      []Location          `json:"locations"`
+}
+
+// Location is a named place to check, with its observation station.
+type Location struct {
+	Name      string  `json:"name"`
+	Label     string  `json:"label,omitempty"`
 	Station   string  `json:"station"`
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
 }
 
-var Default = Config{
+// DisplayName returns the label if set, otherwise the name.
+func (l Location) DisplayName() string {
+	if l.Label != "" {
+		return l.Label
+	}
+	return l.Name
+}
+
+// Default is the location used when detection fails.
+var Default = Location{
+	Name:      "home",
 	Station:   "KEIK",
 	Latitude:  40.0388,
 	Longitude: -105.0412,
 }
 
+// Location returns the named location (case-insensitive), or the default
+// location when name is empty.
+func (c Config) Location(name string) (Location, error) {
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" && len(c.Locations) > 0 {
+		return c.Locations[0], nil
+	}
+	for _, l := range c.Locations {
+		if strings.EqualFold(l.Name, name) {
+			return l, nil
+		}
+	}
+	return Location{}, fmt.Errorf("unknown location %q (configured: %s)", name, strings.Join(c.Names(), ", "))
+}
+
+// DefaultLocation returns the default location, or Default if the config
+// has no locations.
+func (c Config) DefaultLocation() Location {
+	loc, err := c.Location("")
+	if err != nil {
+		return Default
+	}
+	return loc
+}
+
+// Names returns the configured location names in file order.
+func (c Config) Names() []string {
+	names := make([]string, len(c.Locations))
+	for i, l := range c.Locations {
+		names[i] = l.Name
+	}
+	return names
+}
+
+// UnitSystem returns the configured unit system with any per-quantity
+// overrides applied. The default is US units.
+func (c Config) UnitSystem() (units.System, error) {
+	return units.Resolve(c.Units, c.UnitOverrides)
+}
+
+// QCPolicy returns the configured quality control policy, lenient by
+// default.
+func (c Config) QCPolicy() (nws.QCPolicy, error) {
+	return nws.ParseQCPolicy(c.QC)
+}
+
+// StationRunways returns the runways configured for a station (the
+// runways keys are case-insensitive), or nil if it has none.
+func (c Config) StationRunways(station string) ([]aviation.Runway, error) {
+	for id, specs := range c.Runways {
+		if strings.EqualFold(id, station) {
+			runways, err := aviation.ParseRunways(specs)
+			if err != nil {
+				return nil, fmt.Errorf("runways for %s: %w", id, err)
+			}
+			return runways, nil
+		}
+	}
+	return nil, nil
+}
+
+// CrosswindLimitKmh returns the configured crosswind limit in km/h, or
+// aviation.DefaultCrosswindLimit when it isn't set.
+func (c Config) CrosswindLimitKmh() float64 {
+	if c.CrosswindLimit > 0 {
+		return c.CrosswindLimit * 1.852
+	}
+	return aviation.DefaultCrosswindLimit
+}
+
+// Stations returns the distinct stations of all configured locations, in
+// file order.
+func (c Config) Stations() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, l := range c.Locations {
+		id := strings.ToUpper(l.Station)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Validate checks that the config has uniquely named locations and that
+// the default refers to one of them.
+func (c Config) Validate() error {
+	if len(c.Locations) == 0 {
+		return fmt.Errorf("no locations configured")
+	}
+	seen := make(map[string]bool)
+	for _, l := range c.Locations {
+		key := strings.ToLower(l.Name)
+		if key == "" {
+			return fmt.Errorf("location with station %q has no name", l.Station)
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate location name %q", l.Name)
+		}
+		seen[key] = true
+	}
+	if c.Default != "" && !seen[strings.ToLower(c.Default)] {
+		return fmt.Errorf("default location %q is not configured", c.Default)
+	}
+	if _, err := c.UnitSystem(); err != nil {
+		return err
+	}
+	if _, err := c.QCPolicy(); err != nil {
+		return err
+	}
+	for id := range c.Runways {
+		if _, err := c.StationRunways(id); err != nil {
+			return err
+		}
+	}
+	if c.CrosswindLimit < 0 {
+		return fmt.Errorf("crosswind_limit_kt must not be negative")
+	}
+	return nil
+}
+
+// legacyConfig is the original single-location config.json format.
+type legacyConfig struct {
+	Station   *string `json:"station"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
 type geoIPResponse struct {
 	Status  string  `json:"status"`
 	City    string  `json:"city"`
@@ -32,21 +198,6 @@ type geoIPResponse struct {
 	Lon     float64 `json:"lon"`
 }
 
-type nwsPointsResponse struct {
-	Properties struct {
-		ObservationStations string `json:"observationStations"`
-	} `json:"properties"`
-}
-
-type nwsStationsResponse struct {
-	Features []struct {
-		Properties struct {
-			StationIdentifier string `json:"stationIdentifier"`
-			Name              string `json:"name"`
-		} `json:"properties"`
-	} `json:"features"`
-}
-
 // DetectedLocation holds auto-detected location info.
 type DetectedLocation struct {
 	City      string
@@ -55,11 +206,20 @@ type DetectedLocation struct {
 	Longitude float64
 	Station   string
 	StationName string
+	// Nearby lists the stations closest to the detected location,
+	// nearest first.
+	Nearby []nws.StationResponse
 }
 
+// setupChoices is the number of nearby stations offered during setup.
+const setupChoices = 5
+
+// geoIPURL is the IP geolocation endpoint used by DetectLocation.
+var geoIPURL = "http://ip-api.com/json/"
+
 // DetectLocation uses IP geolocation and the NWS API to find the user's
 // nearest weather station. Returns Default values on any failure.
-func DetectLocation(client *http.Client) DetectedLocation {
+func DetectLocation(ctx context.Context, client *nws.Client) DetectedLocation {
 	result := DetectedLocation{
 		Latitude:  Default.Latitude,
 		Longitude: Default.Longitude,
@@ -67,7 +227,7 @@ func DetectLocation(client *http.Client) DetectedLocation {
 	}
 
 	// 1. IP geolocation
-	geo, err := fetchGeoIP(client)
+	geo, err := fetchGeoIP(ctx, client.HTTPClient)
 	if err != nil || geo.Status != "success" {
 		return result
 	}
@@ -76,76 +236,74 @@ func DetectLocation(client *http.Client) DetectedLocation {
 	result.Latitude = geo.Lat
 	result.Longitude = geo.Lon
 
-	// 2. Find nearest NWS station
-	station, name, err := fetchNearestStation(client, geo.Lat, geo.Lon)
-	if err != nil {
-		return result
-	}
-	result.Station = station
-	result.StationName = name
-
+	// 2. Find the nearest NWS stations
+	findStations(ctx, client, &result)
 	return result
 }
 
-func fetchGeoIP(client *http.Client) (geoIPResponse, error) {
-	var geo geoIPResponse
-	resp, err := client.Get("http://ip-api.com/json/")
-	if err != nil {
-		return geo, err
+// LocatePlace finds the NWS stations nearest a place from the offline
+// gazetteer, without IP geolocation. The station falls back to Default
+// on failure.
+func LocatePlace(ctx context.Context, client *nws.Client, p gazetteer.Place) DetectedLocation {
+	result := DetectedLocation{
+		City:      p.Name,
+		Region:    p.State,
+		Latitude:  p.Latitude,
+		Longitude: p.Longitude,
+		Station:   Default.Station,
 	}
-	defer resp.Body.Close()
-	err = json.NewDecoder(resp.Body).Decode(&geo)
-	return geo, err
+	if p.ZIP != "" {
+		result.City, result.Region = p.String(), ""
+	}
+	findStations(ctx, client, &result)
+	return result
 }
 
-func fetchNearestStation(client *http.Client, lat, lon float64) (id, name string, err error) {
-	url := fmt.Sprintf("https://api.weather.gov/points/%.4f,%.4f", lat, lon)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", "", err
+// Place describes the detected location, e.g. "Denver, Colorado".
+func (d DetectedLocation) Place() string {
+	if d.Region == "" {
+		return d.City
 	}
-	req.Header.Set("User-Agent", "(lastwind)")
-	req.Header.Set("Accept", "application/geo+json")
+	return d.City + ", " + d.Region
+}
 
-	resp, err := client.Do(req)
+func findStations(ctx context.Context, client *nws.Client, result *DetectedLocation) {
+	nearby, err := fetchNearbyStations(ctx, client, result.Latitude, result.Longitude)
 	if err != nil {
-		return "", "", err
-	}
-	defer resp.Body.Close()
-
-	var points nwsPointsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&points); err != nil {
-		return "", "", err
-	}
-
-	if points.Properties.ObservationStations == "" {
-		return "", "", fmt.Errorf("no stations URL")
+		return
 	}
+	result.Station = nearby[0].Properties.StationIdentifier
+	result.StationName = nearby[0].Properties.Name
+	result.Nearby = nearby
+}
 
-	req2, err := http.NewRequest("GET", points.Properties.ObservationStations, nil)
+func fetchGeoIP(ctx context.Context, client *http.Client) (geoIPResponse, error) {
+	var geo geoIPResponse
+	req, err := http.NewRequestWithContext(ctx, "GET", geoIPURL, nil)
 	if err != nil {
-		return "", "", err
+		return geo, err
 	}
-	req2.Header.Set("User-Agent", "(lastwind)")
-	req2.Header.Set("Accept", "application/geo+json")
-
-	resp2, err := client.Do(req2)
+	resp, err := client.Do(req)
 	if err != nil {
-		return "", "", err
+		return geo, err
 	}
-	defer resp2.Body.Close()
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&geo)
+	return geo, err
+}
 
-	var stations nwsStationsResponse
-	if err := json.NewDecoder(resp2.Body).Decode(&stations); err != nil {
-		return "", "", err
+// fetchNearbyStations returns up to setupChoices stations near a point,
+// nearest first.
+func fetchNearbyStations(ctx context.Context, client *nws.Client, lat, lon float64) ([]nws.StationResponse, error) {
+	stations, err := client.NearbyStations(ctx, lat, lon)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(stations.Features) == 0 {
-		return "", "", fmt.Errorf("no stations found")
+		return nil, fmt.Errorf("no stations found")
 	}
-
-	s := stations.Features[0].Properties
-	return s.StationIdentifier, s.Name, nil
+	return stations.Features[:min(len(stations.Features), setupChoices)], nil
 }
 
 func Dir() (string, error) {
@@ -156,6 +314,15 @@ func Dir() (string, error) {
 	return filepath.Join(home, ".config", "lastwind"), nil
 }
 
+// CacheDir returns the directory used to cache API responses.
+func CacheDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache"), nil
+}
+
 func Path() (string, error) {
 	dir, err := Dir()
 	if err != nil {
@@ -164,23 +331,43 @@ func Path() (string, error) {
 	return filepath.Join(dir, "config.json"), nil
 }
 
+// Load reads config.json. A file in the original single-location format
+// is migrated to a location named "home" and rewritten.
 func Load() (Config, error) {
 	path, err := Path()
 	if err != nil {
-		return Default, err
+		return Config{}, err
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return Default, err
-		}
-		return Default, err
+		return Config{}, err
 	}
 
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
-		return Default, fmt.Errorf("invalid config file %s: %w", path, err)
+		return Config{}, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if len(cfg.Locations) == 0 {
+		var legacy legacyConfig
+		if err := json.Unmarshal(data, &legacy); err == nil && legacy.Station != nil {
+			cfg = Config{
+				Default: "home",
+				Locations: []Location{{
+					Name:      "home",
+					Station:   *legacy.Station,
+					Latitude:  legacy.Latitude,
+					Longitude: legacy.Longitude,
+				}},
+			}
+			// The migrated config is usable even if it can't be rewritten.
+			Save(cfg)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config file %s: %w", path, err)
 	}
 	return cfg, nil
 }
@@ -211,30 +398,52 @@ func RunSetup(reader *bufio.Reader) (Config, error) {
 	fmt.Println("  ── lastwind configuration ──")
 	fmt.Println()
 	fmt.Println("  No configuration file found. Let's set one up.")
-	fmt.Print("  Detecting your location...")
+	fmt.Println()
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	detected := DetectLocation(client)
+	client := nws.NewClient()
+	client.HTTPClient.Timeout = 10 * time.Second
+	var detected DetectedLocation
+	if place, ok := promptPlace(reader); ok {
+		fmt.Printf("  Finding stations near %s...", place)
+		detected = LocatePlace(context.Background(), client, place)
+		fmt.Println()
+	} else {
+		fmt.Print("  Detecting your location...")
+		detected = DetectLocation(context.Background(), client)
+		if detected.City != "" {
+			fmt.Printf(" found %s\n", detected.Place())
+		} else {
+			fmt.Println(" could not detect, using defaults")
+		}
+	}
 
-	cfg := Config{
+	loc := Location{
+		Name:      Default.Name,
 		Station:   detected.Station,
 		Latitude:  detected.Latitude,
 		Longitude: detected.Longitude,
 	}
 
-	if detected.City != "" {
-		fmt.Printf(" found %s, %s\n", detected.City, detected.Region)
-	} else {
-		fmt.Println(" could not detect, using defaults")
-	}
-	if detected.StationName != "" {
+	if len(detected.Nearby) > 1 {
+		fmt.Println("  Nearest stations:")
+		for i, s := range detected.Nearby {
+			fmt.Printf("    %d. %-5s %s%s\n", i+1, s.Properties.StationIdentifier, s.Properties.Name,
+				stationDistance(s, detected.Latitude, detected.Longitude))
+		}
+	} else if detected.StationName != "" {
 		fmt.Printf("  Nearest station: %s (%s)\n", detected.StationName, detected.Station)
 	}
 	fmt.Println()
 
-	cfg.Station = prompt(reader, "ICAO station code", cfg.Station)
-	cfg.Latitude = promptFloat(reader, "Latitude", cfg.Latitude)
-	cfg.Longitude = promptFloat(reader, "Longitude", cfg.Longitude)
+	if len(detected.Nearby) > 1 {
+		loc.Station = promptStation(reader, detected.Nearby, loc.Station)
+	} else {
+		loc.Station = prompt(reader, "ICAO station code", loc.Station)
+	}
+	loc.Latitude = promptFloat(reader, "Latitude", loc.Latitude)
+	loc.Longitude = promptFloat(reader, "Longitude", loc.Longitude)
+
+	cfg := Config{Default: loc.Name, Locations: []Location{loc}}
 
 	if err := Save(cfg); err != nil {
 		return cfg, fmt.Errorf("failed to save config: %w", err)
@@ -255,6 +464,76 @@ func prompt(reader *bufio.Reader, label string, defaultVal string) string {
 	return strings.ToUpper(input)
 }
 
+// promptPlace asks for a place name or ZIP code to look up in the offline
+// gazetteer, letting the user choose when several places match. It
+// returns false when the answer is empty, to detect the location
+// automatically instead.
+func promptPlace(reader *bufio.Reader) (gazetteer.Place, bool) {
+	for {
+		fmt.Print("  Place or ZIP code (Enter to detect automatically): ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return gazetteer.Place{}, false
+		}
+
+		var p gazetteer.Place
+		var err error
+		if strings.Trim(input, "0123456789-") == "" {
+			p, err = gazetteer.LookupZIP(input)
+		} else {
+			p, err = gazetteer.Lookup(input)
+		}
+		var amb *gazetteer.AmbiguousError
+		if errors.As(err, &amb) {
+			return choosePlace(reader, amb.Matches), true
+		}
+		if err == nil {
+			return p, true
+		}
+		fmt.Printf("  %v\n", err)
+	}
+}
+
+// choosePlace asks which of several matching places was meant.
+func choosePlace(reader *bufio.Reader, matches []gazetteer.Place) gazetteer.Place {
+	fmt.Println("  Several places match:")
+	for i, p := range matches {
+		fmt.Printf("    %d. %s\n", i+1, p)
+	}
+	input := prompt(reader, fmt.Sprintf("Which one (1-%d)", len(matches)), "1")
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 1 || n > len(matches) {
+		fmt.Printf("  Using %s\n", matches[0])
+		return matches[0]
+	}
+	return matches[n-1]
+}
+
+// promptStation asks for a station by its number in the nearby list or
+// by ICAO code.
+func promptStation(reader *bufio.Reader, nearby []nws.StationResponse, defaultVal string) string {
+	input := prompt(reader, fmt.Sprintf("Station (1-%d or ICAO code)", len(nearby)), defaultVal)
+	if n, err := strconv.Atoi(input); err == nil {
+		if n >= 1 && n <= len(nearby) {
+			return nearby[n-1].Properties.StationIdentifier
+		}
+		fmt.Printf("  No station %d, using default %s\n", n, defaultVal)
+		return defaultVal
+	}
+	return input
+}
+
+// stationDistance formats a station's distance from a point, e.g.
+// " (13.5 mi)", or returns "" if it has no coordinates.
+func stationDistance(s nws.StationResponse, lat, lon float64) string {
+	slat, slon, ok := s.Geometry.LatLon()
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" (%.1f mi)", nws.MetersToMiles(geo.Distance(lat, lon, slat, slon)))
+}
+
 func promptFloat(reader *bufio.Reader, label string, defaultVal float64) float64 {
 	fmt.Printf("  %s [%.4f]: ", label, defaultVal)
 	input, _ := reader.ReadString('\n')
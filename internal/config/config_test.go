@@ -2,6 +2,7 @@ package config
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,6 +11,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"lastwind/internal/aviation"
+	"lastwind/internal/gazetteer"
+	"lastwind/internal/nws"
 )
 
 func TestSaveAndLoad(t *testing.T) {
@@ -19,9 +24,11 @@ func TestSaveAndLoad(t *testing.T) {
 	defer os.Setenv("HOME", origHome)
 
 	cfg := Config{
-		Station:   "KDEN",
-		Latitude:  39.8561,
-		Longitude: -104.6737,
+		Default: "office",
+		Locations: []Location{
+			{Name: "home", Station: "KBJC", Latitude: 39.9, Longitude: -105.1},
+			{Name: "office", Label: "Main Office", Station: "KDEN", Latitude: 39.8561, Longitude: -104.6737},
+		},
 	}
 
 	if err := Save(cfg); err != nil {
@@ -39,14 +46,163 @@ func TestSaveAndLoad(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
-	if loaded.Station != cfg.Station {
-		t.Errorf("Station = %q, want %q", loaded.Station, cfg.Station)
+	if loaded.Default != cfg.Default || len(loaded.Locations) != 2 {
+		t.Fatalf("loaded = %+v, want %+v", loaded, cfg)
+	}
+	for i, l := range loaded.Locations {
+		if l != cfg.Locations[i] {
+			t.Errorf("Locations[%d] = %+v, want %+v", i, l, cfg.Locations[i])
+		}
+	}
+}
+
+func TestLoad_MigratesLegacyConfig(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+
+	dir := filepath.Join(tmp, ".config", "lastwind")
+	os.MkdirAll(dir, 0755)
+	legacy := `{"station": "KDEN", "latitude": 39.8561, "longitude": -104.6737}`
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(legacy), 0644)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := Location{Name: "home", Station: "KDEN", Latitude: 39.8561, Longitude: -104.6737}
+	if cfg.Default != "home" || len(cfg.Locations) != 1 || cfg.Locations[0] != want {
+		t.Errorf("migrated config = %+v", cfg)
 	}
-	if loaded.Latitude != cfg.Latitude {
-		t.Errorf("Latitude = %v, want %v", loaded.Latitude, cfg.Latitude)
+
+	// The file is rewritten in the new format
+	data, _ := os.ReadFile(filepath.Join(dir, "config.json"))
+	if !strings.Contains(string(data), `"locations"`) {
+		t.Errorf("config file not migrated:\n%s", data)
 	}
-	if loaded.Longitude != cfg.Longitude {
-		t.Errorf("Longitude = %v, want %v", loaded.Longitude, cfg.Longitude)
+}
+
+func TestLoad_InvalidConfig(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+
+	dir := filepath.Join(tmp, ".config", "lastwind")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"default": "work", "locations": [{"name": "home"}]}`), 0644)
+
+	if _, err := Load(); err == nil {
+		t.Fatal("Load() expected error for unknown default location")
+	}
+}
+
+func TestConfig_Location(t *testing.T) {
+	cfg := Config{
+		Default: "office",
+		Locations: []Location{
+			{Name: "home", Station: "KBJC"},
+			{Name: "Office", Station: "KDEN"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "KDEN", false},
+		{"home", "KBJC", false},
+		{"HOME", "KBJC", false},
+		{"site", "", true},
+	}
+	for _, tt := range tests {
+		loc, err := cfg.Location(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Location(%q) error = %v", tt.name, err)
+			continue
+		}
+		if loc.Station != tt.want {
+			t.Errorf("Location(%q) = %q, want %q", tt.name, loc.Station, tt.want)
+		}
+	}
+
+	_, err := cfg.Location("site")
+	if err == nil || !strings.Contains(err.Error(), "home, Office") {
+		t.Errorf("Location(site) error = %v, want list of names", err)
+	}
+
+	cfg.Default = ""
+	if loc := cfg.DefaultLocation(); loc.Station != "KBJC" {
+		t.Errorf("DefaultLocation() without default = %q, want first location", loc.Station)
+	}
+	if loc := (Config{}).DefaultLocation(); loc != Default {
+		t.Errorf("DefaultLocation() of empty config = %+v, want Default", loc)
+	}
+}
+
+func TestConfig_Stations(t *testing.T) {
+	cfg := Config{Locations: []Location{
+		{Name: "home", Station: "KBJC"},
+		{Name: "office", Station: "KDEN"},
+		{Name: "site", Station: "kbjc"},
+	}}
+	got := strings.Join(cfg.Stations(), ",")
+	if got != "KBJC,KDEN" {
+		t.Errorf("Stations() = %q, want KBJC,KDEN", got)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"valid", Config{Default: "a", Locations: []Location{{Name: "a"}, {Name: "b"}}}, false},
+		{"no default", Config{Locations: []Location{{Name: "a"}}}, false},
+		{"empty", Config{}, true},
+		{"unnamed", Config{Locations: []Location{{Station: "KDEN"}}}, true},
+		{"duplicate", Config{Locations: []Location{{Name: "a"}, {Name: "A"}}}, true},
+		{"bad default", Config{Default: "c", Locations: []Location{{Name: "a"}}}, true},
+		{"units", Config{Units: "metric", UnitOverrides: map[string]string{"pressure": "inHg"}, Locations: []Location{{Name: "a"}}}, false},
+		{"bad units", Config{Units: "nautical", Locations: []Location{{Name: "a"}}}, true},
+		{"qc", Config{QC: "strict", Locations: []Location{{Name: "a"}}}, false},
+		{"bad qc", Config{QC: "paranoid", Locations: []Location{{Name: "a"}}}, true},
+		{"bad override", Config{UnitOverrides: map[string]string{"speed": "furlongs"}, Locations: []Location{{Name: "a"}}}, true},
+		{"runways", Config{Runways: map[string][]string{"KBJC": {"03/21", "12L/30R=117"}}, CrosswindLimit: 12, Locations: []Location{{Name: "a"}}}, false},
+		{"bad runway", Config{Runways: map[string][]string{"KBJC": {"12X"}}, Locations: []Location{{Name: "a"}}}, true},
+		{"negative crosswind limit", Config{CrosswindLimit: -1, Locations: []Location{{Name: "a"}}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_StationRunways(t *testing.T) {
+	cfg := Config{Runways: map[string][]string{"kbjc": {"03/21", "12L/30R"}}}
+	runways, err := cfg.StationRunways("KBJC")
+	if err != nil || len(runways) != 4 || runways[3].Designator != "30R" {
+		t.Errorf("StationRunways(KBJC) = %v, %v", runways, err)
+	}
+	if runways, err := cfg.StationRunways("KDEN"); err != nil || runways != nil {
+		t.Errorf("StationRunways(KDEN) = %v, %v, want none", runways, err)
+	}
+
+	if got := cfg.CrosswindLimitKmh(); got != aviation.DefaultCrosswindLimit {
+		t.Errorf("CrosswindLimitKmh() = %v, want the default", got)
+	}
+	cfg.CrosswindLimit = 10
+	if got := cfg.CrosswindLimitKmh(); got != 18.52 {
+		t.Errorf("CrosswindLimitKmh() = %v, want 18.52", got)
+	}
+}
+
+func TestLocation_DisplayName(t *testing.T) {
+	if got := (Location{Name: "office"}).DisplayName(); got != "office" {
+		t.Errorf("DisplayName() = %q, want office", got)
+	}
+	if got := (Location{Name: "office", Label: "Main Office"}).DisplayName(); got != "Main Office" {
+		t.Errorf("DisplayName() = %q, want Main Office", got)
 	}
 }
 
@@ -81,15 +237,15 @@ func TestLoadOrSetup_ExistingConfig(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("HOME", tmp)
 
-	expected := Config{Station: "KBJC", Latitude: 39.9, Longitude: -105.1}
+	expected := Config{Locations: []Location{{Name: "home", Station: "KBJC", Latitude: 39.9, Longitude: -105.1}}}
 	Save(expected)
 
 	cfg, err := LoadOrSetup()
 	if err != nil {
 		t.Fatalf("LoadOrSetup() error = %v", err)
 	}
-	if cfg.Station != expected.Station {
-		t.Errorf("Station = %q, want %q", cfg.Station, expected.Station)
+	if loc := cfg.DefaultLocation(); loc.Station != "KBJC" {
+		t.Errorf("Station = %q, want %q", loc.Station, "KBJC")
 	}
 }
 
@@ -103,6 +259,16 @@ func TestDir(t *testing.T) {
 	}
 }
 
+func TestCacheDir(t *testing.T) {
+	dir, err := CacheDir()
+	if err != nil {
+		t.Fatalf("CacheDir() error = %v", err)
+	}
+	if !strings.HasSuffix(dir, filepath.Join(".config", "lastwind", "cache")) {
+		t.Errorf("CacheDir() = %q, expected to end with .config/lastwind/cache", dir)
+	}
+}
+
 func TestPath(t *testing.T) {
 	path, err := Path()
 	if err != nil {
@@ -115,8 +281,8 @@ func TestPath(t *testing.T) {
 
 // --- DetectLocation tests ---
 
-func mockGeoIPServer(status string, city string, region string, lat, lon float64) *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+func mockGeoIPServer(t *testing.T, status string, city string, region string, lat, lon float64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(geoIPResponse{
 			Status: status,
 			City:   city,
@@ -125,6 +291,13 @@ func mockGeoIPServer(status string, city string, region string, lat, lon float64
 			Lon:    lon,
 		})
 	}))
+	orig := geoIPURL
+	geoIPURL = server.URL
+	t.Cleanup(func() {
+		geoIPURL = orig
+		server.Close()
+	})
+	return server
 }
 
 func mockNWSServer(stationID, stationName string) *httptest.Server {
@@ -143,44 +316,39 @@ func mockNWSServer(stationID, stationName string) *httptest.Server {
 	return httptest.NewServer(mux)
 }
 
+func testClient(baseURL string) *nws.Client {
+	c := nws.NewClient()
+	c.BaseURL = baseURL
+	c.Retry = nws.RetryPolicy{}
+	return c
+}
+
 func TestDetectLocation_Success(t *testing.T) {
-	geoServer := mockGeoIPServer("success", "Denver", "Colorado", 39.7392, -104.9903)
-	defer geoServer.Close()
+	mockGeoIPServer(t, "success", "Denver", "Colorado", 39.7392, -104.9903)
 
 	nwsServer := mockNWSServer("KDEN", "Denver International Airport")
 	defer nwsServer.Close()
 
-	// We need to override the URLs used by DetectLocation.
-	// Test fetchGeoIP and fetchNearestStation directly instead.
-	client := geoServer.Client()
+	result := DetectLocation(context.Background(), testClient(nwsServer.URL))
 
-	geo, err := fetchGeoIP(client)
-	if err != nil {
-		t.Fatalf("fetchGeoIP error: %v", err)
+	if result.City != "Denver" || result.Region != "Colorado" {
+		t.Errorf("location = %q, %q, want Denver, Colorado", result.City, result.Region)
 	}
-	// The client from httptest won't resolve ip-api.com, so test the mock directly
-	resp, err := client.Get(geoServer.URL)
-	if err != nil {
-		t.Fatalf("GET error: %v", err)
+	if result.Latitude != 39.7392 || result.Longitude != -104.9903 {
+		t.Errorf("coordinates = %v, %v", result.Latitude, result.Longitude)
+	}
+	if result.Station != "KDEN" || result.StationName != "Denver International Airport" {
+		t.Errorf("station = %q (%q), want KDEN", result.Station, result.StationName)
 	}
-	resp.Body.Close()
-	_ = geo
 }
 
 func TestFetchGeoIP_Success(t *testing.T) {
-	server := mockGeoIPServer("success", "Boulder", "Colorado", 40.015, -105.27)
-	defer server.Close()
+	server := mockGeoIPServer(t, "success", "Boulder", "Colorado", 40.015, -105.27)
 
-	// Override the URL by hitting the test server directly
-	client := server.Client()
-	resp, err := client.Get(server.URL)
+	geo, err := fetchGeoIP(context.Background(), server.Client())
 	if err != nil {
-		t.Fatalf("error: %v", err)
+		t.Fatalf("fetchGeoIP() error = %v", err)
 	}
-	defer resp.Body.Close()
-
-	var geo geoIPResponse
-	json.NewDecoder(resp.Body).Decode(&geo)
 
 	if geo.Status != "success" {
 		t.Errorf("Status = %q, want success", geo.Status)
@@ -193,58 +361,61 @@ func TestFetchGeoIP_Success(t *testing.T) {
 	}
 }
 
-func TestFetchNearestStation_Success(t *testing.T) {
+func TestFetchNearbyStations_Success(t *testing.T) {
 	server := mockNWSServer("KBJC", "Broomfield Jeffco")
 	defer server.Close()
 
-	// Override fetchNearestStation to use test server URL
-	client := server.Client()
-
-	// Call the points endpoint on our mock
-	url := server.URL + "/points/40.0000,-105.0000"
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("User-Agent", "(lastwind)")
-	req.Header.Set("Accept", "application/geo+json")
-	resp, err := client.Do(req)
+	stations, err := fetchNearbyStations(context.Background(), testClient(server.URL), 40.0, -105.0)
 	if err != nil {
-		t.Fatalf("points request error: %v", err)
+		t.Fatalf("fetchNearbyStations() error = %v", err)
 	}
-	defer resp.Body.Close()
-
-	var points nwsPointsResponse
-	json.NewDecoder(resp.Body).Decode(&points)
-
-	if points.Properties.ObservationStations == "" {
-		t.Fatal("expected stations URL")
+	if len(stations) != 1 {
+		t.Fatalf("fetchNearbyStations() = %d stations, want 1", len(stations))
+	}
+	if s := stations[0].Properties; s.StationIdentifier != "KBJC" || s.Name != "Broomfield Jeffco" {
+		t.Errorf("station = %q (%q), want KBJC (Broomfield Jeffco)", s.StationIdentifier, s.Name)
 	}
+}
+
+func TestFetchNearbyStations_Limit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/points/") {
+			fmt.Fprintf(w, `{"properties":{"observationStations":"http://%s/stations"}}`, r.Host)
+			return
+		}
+		var features []string
+		for i := range 8 {
+			features = append(features, fmt.Sprintf(`{"properties":{"stationIdentifier":"K%03d"}}`, i))
+		}
+		fmt.Fprintf(w, `{"features":[%s]}`, strings.Join(features, ","))
+	}))
+	defer server.Close()
 
-	// Follow the stations URL
-	req2, _ := http.NewRequest("GET", points.Properties.ObservationStations, nil)
-	req2.Header.Set("User-Agent", "(lastwind)")
-	resp2, err := client.Do(req2)
+	stations, err := fetchNearbyStations(context.Background(), testClient(server.URL), 40.0, -105.0)
 	if err != nil {
-		t.Fatalf("stations request error: %v", err)
+		t.Fatalf("fetchNearbyStations() error = %v", err)
 	}
-	defer resp2.Body.Close()
+	if len(stations) != setupChoices || stations[0].Properties.StationIdentifier != "K000" {
+		t.Errorf("fetchNearbyStations() = %d stations, want the nearest %d", len(stations), setupChoices)
+	}
+}
 
-	var stations nwsStationsResponse
-	json.NewDecoder(resp2.Body).Decode(&stations)
+func TestFetchNearbyStations_NoStations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"properties":{}}`))
+	}))
+	defer server.Close()
 
-	if len(stations.Features) == 0 {
-		t.Fatal("expected at least one station")
-	}
-	if stations.Features[0].Properties.StationIdentifier != "KBJC" {
-		t.Errorf("station = %q, want KBJC", stations.Features[0].Properties.StationIdentifier)
+	if _, err := fetchNearbyStations(context.Background(), testClient(server.URL), 40.0, -105.0); err == nil {
+		t.Fatal("fetchNearbyStations() expected error for missing stations URL")
 	}
 }
 
 func TestDetectLocation_GeoIPFail(t *testing.T) {
 	// Server that returns an error status
-	server := mockGeoIPServer("fail", "", "", 0, 0)
-	defer server.Close()
+	mockGeoIPServer(t, "fail", "", "", 0, 0)
 
-	client := server.Client()
-	result := DetectLocation(client)
+	result := DetectLocation(context.Background(), testClient("http://localhost:1"))
 
 	// Should fall back to defaults
 	if result.Station != Default.Station {
@@ -252,6 +423,20 @@ func TestDetectLocation_GeoIPFail(t *testing.T) {
 	}
 }
 
+func TestDetectLocation_NWSFail(t *testing.T) {
+	mockGeoIPServer(t, "success", "Denver", "Colorado", 39.7392, -104.9903)
+
+	result := DetectLocation(context.Background(), testClient("http://localhost:1"))
+
+	// Location is detected but the station falls back to the default
+	if result.City != "Denver" {
+		t.Errorf("City = %q, want Denver", result.City)
+	}
+	if result.Station != Default.Station {
+		t.Errorf("Station = %q, want default %q", result.Station, Default.Station)
+	}
+}
+
 func TestPrompt(t *testing.T) {
 	reader := bufio.NewReader(strings.NewReader("kden\n"))
 	got := prompt(reader, "Station", "KEIK")
@@ -266,6 +451,71 @@ func TestPrompt(t *testing.T) {
 	}
 }
 
+func TestLocatePlace(t *testing.T) {
+	server := mockNWSServer("KBJC", "Broomfield Jeffco")
+	defer server.Close()
+
+	result := LocatePlace(context.Background(), testClient(server.URL),
+		gazetteer.Place{Name: "Boulder", State: "CO", Latitude: 40.015, Longitude: -105.2705})
+	if result.Place() != "Boulder, CO" || result.Latitude != 40.015 {
+		t.Errorf("LocatePlace() = %+v", result)
+	}
+	if result.Station != "KBJC" || len(result.Nearby) != 1 {
+		t.Errorf("station = %q with %d nearby, want KBJC", result.Station, len(result.Nearby))
+	}
+
+	result = LocatePlace(context.Background(), testClient("http://localhost:1"), gazetteer.Place{ZIP: "80301"})
+	if result.Place() != "ZIP 80301" || result.Station != Default.Station {
+		t.Errorf("LocatePlace(ZIP) with NWS down = %+v", result)
+	}
+}
+
+func TestPromptPlace(t *testing.T) {
+	tests := []struct {
+		name, input string
+		want        string
+		ok          bool
+	}{
+		{"empty", "\n", "", false},
+		{"place", "boulder co\n", "Boulder, CO", true},
+		{"zip", "80301\n", "ZIP 80301", true},
+		{"retry after unknown", "Gotham\nDenver\n", "Denver, CO", true},
+		{"ambiguous", "Springfield\n3\n", "Springfield, MO", true},
+		{"ambiguous default", "Portland\n\n", "Portland, ME", true},
+		{"eof", "", "", false},
+	}
+	for _, tt := range tests {
+		p, ok := promptPlace(bufio.NewReader(strings.NewReader(tt.input)))
+		if ok != tt.ok || (ok && p.String() != tt.want) {
+			t.Errorf("%s: promptPlace() = %s, %v, want %s, %v", tt.name, p, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPromptStation(t *testing.T) {
+	var nearby []nws.StationResponse
+	for _, id := range []string{"KDEN", "KBJC", "KAPA"} {
+		var s nws.StationResponse
+		s.Properties.StationIdentifier = id
+		nearby = append(nearby, s)
+	}
+
+	tests := []struct {
+		input, want string
+	}{
+		{"2\n", "KBJC"},
+		{"\n", "KDEN"},
+		{"keik\n", "KEIK"},
+		{"9\n", "KDEN"},
+	}
+	for _, tt := range tests {
+		got := promptStation(bufio.NewReader(strings.NewReader(tt.input)), nearby, "KDEN")
+		if got != tt.want {
+			t.Errorf("promptStation(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestPromptFloat(t *testing.T) {
 	reader := bufio.NewReader(strings.NewReader("39.85\n"))
 	got := promptFloat(reader, "Lat", 40.0)
@@ -0,0 +1,329 @@
+// Package gazetteer geocodes US place names and ZIP codes offline from
+// tables embedded in the binary.
+//
+// The bundled tables cover major cities, state capitals and a sample of
+// ZIP codes, gzip-compressed. Regenerate them from the Census Bureau's
+// national Gazetteer files of places and ZIP Code Tabulation Areas for
+// full coverage:
+//
+//	go run ./internal/gazetteer/gen -places 2023_Gaz_place_national.txt -zips 2023_Gaz_zcta_national.txt
+package gazetteer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed places.tsv.gz
+var placesTSV []byte
+
+//go:embed zips.tsv.gz
+var zipsTSV []byte
+
+// Place is a geocoded place or ZIP code centroid.
+type Place struct {
+	Name      string
+	State     string
+	ZIP       string
+	Latitude  float64
+	Longitude float64
+}
+
+// String returns the place as "Boulder, CO" or "ZIP 80301".
+func (p Place) String() string {
+	if p.ZIP != "" {
+		return "ZIP " + p.ZIP
+	}
+	return p.Name + ", " + p.State
+}
+
+// AmbiguousError is returned when a query matches several places equally
+// well. Matches lists them for the user to choose from.
+type AmbiguousError struct {
+	Query   string
+	Matches []Place
+}
+
+func (e *AmbiguousError) Error() string {
+	names := make([]string, len(e.Matches))
+	for i, p := range e.Matches {
+		names[i] = p.String()
+	}
+	return fmt.Sprintf("%q matches %d places: %s", e.Query, len(e.Matches), strings.Join(names, "; "))
+}
+
+var (
+	loadOnce sync.Once
+	loadErr  error
+	places   []Place
+	zips     map[string]Place
+)
+
+// loaded decodes the embedded tables the first time it's called and
+// returns the error, if any, of doing so.
+func loaded() error {
+	loadOnce.Do(func() { loadErr = load() })
+	return loadErr
+}
+
+func load() error {
+	placeRows, err := readTSV(placesTSV, 4)
+	if err != nil {
+		return fmt.Errorf("reading embedded places: %w", err)
+	}
+	zipRows, err := readTSV(zipsTSV, 3)
+	if err != nil {
+		return fmt.Errorf("reading embedded ZIP codes: %w", err)
+	}
+
+	places = nil
+	for _, f := range placeRows {
+		lat, lon, ok := parseCoords(f[2], f[3])
+		if ok {
+			places = append(places, Place{Name: f[0], State: f[1], Latitude: lat, Longitude: lon})
+		}
+	}
+	zips = make(map[string]Place)
+	for _, f := range zipRows {
+		lat, lon, ok := parseCoords(f[1], f[2])
+		if ok {
+			zips[f[0]] = Place{ZIP: f[0], Latitude: lat, Longitude: lon}
+		}
+	}
+	return nil
+}
+
+// readTSV returns the rows of a gzip-compressed tab-separated table with
+// at least n fields, skipping blank lines and # comments.
+func readTSV(data []byte, n int) ([][]string, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	sc := bufio.NewScanner(bytes.NewReader(raw))
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if f := strings.Split(line, "\t"); len(f) >= n {
+			rows = append(rows, f)
+		}
+	}
+	return rows, sc.Err()
+}
+
+func parseCoords(lat, lon string) (float64, float64, bool) {
+	la, err1 := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	lo, err2 := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	return la, lo, err1 == nil && err2 == nil
+}
+
+// LookupZIP returns the centroid of a five-digit ZIP code.
+func LookupZIP(zip string) (Place, error) {
+	if err := loaded(); err != nil {
+		return Place{}, err
+	}
+	zip = strings.TrimSpace(zip)
+	if len(zip) > 5 && zip[5] == '-' {
+		zip = zip[:5] // ZIP+4
+	}
+	if p, ok := zips[zip]; ok {
+		return p, nil
+	}
+	if len(zip) != 5 || strings.Trim(zip, "0123456789") != "" {
+		return Place{}, fmt.Errorf("invalid ZIP code %q", zip)
+	}
+	return Place{}, fmt.Errorf("ZIP code %s is not in the offline gazetteer", zip)
+}
+
+// Search returns the places that best match a query such as "Boulder,
+// CO", "boulder colorado" or "St Louis". Names are matched ignoring case
+// and punctuation, with common abbreviations expanded; an exact name beats
+// a prefix, which beats a substring, which beats a near miss of one or two
+// typos. Only the best tier of matches is returned, sorted by name and
+// state. It returns nil if the embedded tables can't be read; Lookup
+// reports that error.
+func Search(query string) []Place {
+	if loaded() != nil {
+		return nil
+	}
+	name, state := splitState(query)
+	q := normalize(name)
+	if q == "" {
+		return nil
+	}
+
+	best := -1
+	var matches []Place
+	for _, p := range places {
+		if state != "" && p.State != state {
+			continue
+		}
+		s := score(normalize(p.Name), q)
+		switch {
+		case s < 0:
+		case best < 0 || s < best:
+			best, matches = s, []Place{p}
+		case s == best:
+			matches = append(matches, p)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Name != matches[j].Name {
+			return matches[i].Name < matches[j].Name
+		}
+		return matches[i].State < matches[j].State
+	})
+	return matches
+}
+
+// Lookup returns the single place matching query, or an *AmbiguousError
+// listing the candidates when there are several.
+func Lookup(query string) (Place, error) {
+	if err := loaded(); err != nil {
+		return Place{}, err
+	}
+	matches := Search(query)
+	switch len(matches) {
+	case 0:
+		return Place{}, fmt.Errorf("no place matching %q in the offline gazetteer", query)
+	case 1:
+		return matches[0], nil
+	}
+	return Place{}, &AmbiguousError{Query: query, Matches: matches}
+}
+
+// score ranks how well a normalized name matches a normalized query:
+// 0 exact, 1 prefix, 2 substring, 3 or 4 for one or two typos, or -1 for
+// no match.
+func score(name, q string) int {
+	switch {
+	case name == q:
+		return 0
+	case strings.HasPrefix(name, q):
+		return 1
+	case len(q) >= 3 && strings.Contains(name, q):
+		return 2
+	}
+	maxEdits := 1
+	if len(q) >= 8 {
+		maxEdits = 2
+	}
+	if len(q) >= 4 {
+		if d := levenshtein(name, q); d <= maxEdits {
+			return 2 + d
+		}
+	}
+	return -1
+}
+
+// splitState separates a trailing state, given as a postal code or full
+// name after a comma or the last word(s), from a place query.
+func splitState(query string) (name, state string) {
+	query = strings.TrimSpace(query)
+	if i := strings.LastIndex(query, ","); i >= 0 {
+		if s, ok := parseState(query[i+1:]); ok {
+			return query[:i], s
+		}
+		return query[:i], ""
+	}
+	words := strings.Fields(query)
+	for n := min(3, len(words)-1); n >= 1; n-- {
+		if s, ok := parseState(strings.Join(words[len(words)-n:], " ")); ok {
+			return strings.Join(words[:len(words)-n], " "), s
+		}
+	}
+	return query, ""
+}
+
+func parseState(s string) (string, bool) {
+	s = strings.ToUpper(strings.TrimSpace(strings.ReplaceAll(s, ".", "")))
+	if _, ok := stateNames[s]; ok {
+		return s, true
+	}
+	for code, name := range stateNames {
+		if strings.ToUpper(strings.ReplaceAll(name, ".", "")) == s {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+var abbreviations = map[string]string{
+	"st": "saint", "ste": "sainte", "ft": "fort", "mt": "mount", "pt": "point",
+}
+
+// normalize lowercases a name, drops punctuation and expands common
+// abbreviations, so "St. Louis" and "saint louis" compare equal.
+func normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == ' ':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		case r == '-' || r == '/':
+			return ' '
+		}
+		return -1
+	}, s)
+	words := strings.Fields(s)
+	for i, w := range words {
+		if full, ok := abbreviations[w]; ok {
+			words[i] = full
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+var stateNames = map[string]string{
+	"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas", "CA": "California",
+	"CO": "Colorado", "CT": "Connecticut", "DE": "Delaware", "DC": "District of Columbia",
+	"FL": "Florida", "GA": "Georgia", "HI": "Hawaii", "ID": "Idaho", "IL": "Illinois",
+	"IN": "Indiana", "IA": "Iowa", "KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana",
+	"ME": "Maine", "MD": "Maryland", "MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota",
+	"MS": "Mississippi", "MO": "Missouri", "MT": "Montana", "NE": "Nebraska", "NV": "Nevada",
+	"NH": "New Hampshire", "NJ": "New Jersey", "NM": "New Mexico", "NY": "New York",
+	"NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio", "OK": "Oklahoma", "OR": "Oregon",
+	"PA": "Pennsylvania", "RI": "Rhode Island", "SC": "South Carolina", "SD": "South Dakota",
+	"TN": "Tennessee", "TX": "Texas", "UT": "Utah", "VT": "Vermont", "VA": "Virginia",
+	"WA": "Washington", "WV": "West Virginia", "WI": "Wisconsin", "WY": "Wyoming",
+	"AS": "American Samoa", "GU": "Guam", "MP": "Northern Mariana Islands", "PR": "Puerto Rico",
+	"VI": "U.S. Virgin Islands",
+}
@@ -0,0 +1,118 @@
+package gazetteer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"Boulder, CO", "Boulder, CO"},
+		{"boulder colorado", "Boulder, CO"},
+		{"Boulder", "Boulder, CO"},
+		{"St Louis", "St. Louis, MO"},
+		{"saint louis, mo", "St. Louis, MO"},
+		{"Portland, Maine", "Portland, ME"},
+		{"Salt Lake", "Salt Lake City, UT"},
+		{"Albuquerqe", "Albuquerque, NM"},
+		{"Ft Collins", "Fort Collins, CO"},
+		{"New York", "New York, NY"},
+		{"Carson City NV", "Carson City, NV"},
+	}
+	for _, tt := range tests {
+		p, err := Lookup(tt.query)
+		if err != nil {
+			t.Errorf("Lookup(%q) error = %v", tt.query, err)
+			continue
+		}
+		if p.String() != tt.want {
+			t.Errorf("Lookup(%q) = %s, want %s", tt.query, p, tt.want)
+		}
+	}
+}
+
+func TestLookup_Ambiguous(t *testing.T) {
+	_, err := Lookup("Springfield")
+	var amb *AmbiguousError
+	if !errors.As(err, &amb) {
+		t.Fatalf("Lookup(Springfield) error = %v, want *AmbiguousError", err)
+	}
+	var got []string
+	for _, p := range amb.Matches {
+		got = append(got, p.String())
+	}
+	want := []string{"Springfield, IL", "Springfield, MA", "Springfield, MO"}
+	if len(got) != len(want) {
+		t.Fatalf("matches = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("matches = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestLookup_NotFound(t *testing.T) {
+	for _, q := range []string{"Gotham", "Boulder, WY", ""} {
+		if p, err := Lookup(q); err == nil {
+			t.Errorf("Lookup(%q) = %s, want error", q, p)
+		}
+	}
+}
+
+func TestLookupZIP(t *testing.T) {
+	p, err := LookupZIP("80301")
+	if err != nil {
+		t.Fatalf("LookupZIP() error = %v", err)
+	}
+	if p.String() != "ZIP 80301" || p.Latitude < 40 || p.Latitude > 40.1 {
+		t.Errorf("LookupZIP(80301) = %+v", p)
+	}
+	if _, err := LookupZIP("80301-1234"); err != nil {
+		t.Errorf("LookupZIP(ZIP+4) error = %v", err)
+	}
+	for _, zip := range []string{"8030", "abcde", "00000"} {
+		if _, err := LookupZIP(zip); err == nil {
+			t.Errorf("LookupZIP(%q) expected error", zip)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"denver", "denver", 0},
+		{"denver", "denvr", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLookup_CorruptTable(t *testing.T) {
+	saved := placesTSV
+	defer func() {
+		placesTSV = saved
+		loadOnce, loadErr = sync.Once{}, nil
+	}()
+	placesTSV = []byte("not gzip")
+	loadOnce, loadErr = sync.Once{}, nil
+
+	if _, err := Lookup("Boulder, CO"); err == nil {
+		t.Error("Lookup() expected an error for a corrupt table")
+	}
+	if _, err := LookupZIP("80301"); err == nil {
+		t.Error("LookupZIP() expected an error for a corrupt table")
+	}
+}
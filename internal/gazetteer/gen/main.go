@@ -0,0 +1,125 @@
+// Command gen rebuilds the gazetteer's embedded tables from the Census
+// Bureau's national Gazetteer files (places and ZCTAs), which are
+// tab-separated with a header row:
+//
+//	go run ./internal/gazetteer/gen -places 2023_Gaz_place_national.txt -zips 2023_Gaz_zcta_national.txt
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// placeSuffixes are the legal/statistical area descriptions the Census
+// appends to place names, longest first.
+var placeSuffixes = []string{
+	" city and borough", " unified government (balance)", " metropolitan government (balance)",
+	" consolidated government (balance)", " (balance)", " municipality", " borough", " village",
+	" city", " town", " CDP",
+}
+
+func main() {
+	placesPath := flag.String("places", "", "Census Gazetteer places file")
+	zipsPath := flag.String("zips", "", "Census Gazetteer ZCTA file")
+	out := flag.String("out", "internal/gazetteer", "directory to write places.tsv.gz and zips.tsv.gz to")
+	flag.Parse()
+
+	if *placesPath == "" && *zipsPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: give -places and/or -zips")
+		os.Exit(2)
+	}
+	if *placesPath != "" {
+		if err := convert(*placesPath, filepath.Join(*out, "places.tsv.gz"), "# name\tstate\tlatitude\tlongitude", placeRow); err != nil {
+			fmt.Fprintf(os.Stderr, "Error converting places: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *zipsPath != "" {
+		if err := convert(*zipsPath, filepath.Join(*out, "zips.tsv.gz"), "# zip\tlatitude\tlongitude", zipRow); err != nil {
+			fmt.Fprintf(os.Stderr, "Error converting ZIP codes: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func placeRow(col func(string) string) string {
+	name := col("NAME")
+	for _, suffix := range placeSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			name = strings.TrimSuffix(name, suffix)
+			break
+		}
+	}
+	return strings.Join([]string{name, col("USPS"), col("INTPTLAT"), col("INTPTLONG")}, "\t")
+}
+
+func zipRow(col func(string) string) string {
+	return strings.Join([]string{col("GEOID"), col("INTPTLAT"), col("INTPTLONG")}, "\t")
+}
+
+// convert reads a Gazetteer file and writes one row per record, sorted and
+// gzip-compressed.
+func convert(in, out, header string, row func(col func(string) string) string) error {
+	f, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := readRows(f, row)
+	if err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+	sort.Strings(rows)
+	data := header + "\n" + strings.Join(rows, "\n") + "\n"
+
+	w, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	zw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		w.Close()
+		return err
+	}
+	if _, err := io.WriteString(zw, data); err != nil {
+		w.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func readRows(r io.Reader, row func(col func(string) string) string) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	if !sc.Scan() {
+		return nil, fmt.Errorf("missing header")
+	}
+	index := make(map[string]int)
+	for i, name := range strings.Split(sc.Text(), "\t") {
+		index[strings.TrimSpace(name)] = i
+	}
+
+	var rows []string
+	for sc.Scan() {
+		fields := strings.Split(sc.Text(), "\t")
+		col := func(name string) string {
+			if i, ok := index[name]; ok && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+		rows = append(rows, row(col))
+	}
+	return rows, sc.Err()
+}
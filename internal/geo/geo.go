@@ -0,0 +1,34 @@
+// Package geo computes great-circle distances and bearings between
+// coordinates.
+package geo
+
+import "math"
+
+// EarthRadius is the mean radius of the Earth in metres.
+const EarthRadius = 6371008.8
+
+// Distance returns the great-circle distance in metres between two points
+// given in decimal degrees, using the haversine formula.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	dPhi := phi2 - phi1
+	dLon := radians(lon2 - lon1)
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Bearing returns the initial bearing in degrees (0–360, clockwise from
+// north) from the first point to the second.
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	dLon := radians(lon2 - lon1)
+
+	y := math.Sin(dLon) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLon)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+func radians(d float64) float64 { return d * math.Pi / 180 }
+
+func degrees(r float64) float64 { return r * 180 / math.Pi }
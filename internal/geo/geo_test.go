@@ -0,0 +1,45 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64 // metres
+	}{
+		{"same point", 39.8466, -104.6562, 39.8466, -104.6562, 0},
+		{"KDEN to KBJC", 39.8466, -104.6562, 39.9088, -105.1172, 39942},
+		{"one degree of latitude", 0, 0, 1, 0, 111195},
+		{"antipodes", 0, 0, 0, 180, math.Pi * EarthRadius},
+	}
+	for _, tt := range tests {
+		got := Distance(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+		if math.Abs(got-tt.want) > 100 {
+			t.Errorf("%s: Distance() = %.0f m, want %.0f m", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBearing(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"north", 0, 0, 1, 0, 0},
+		{"east", 0, 0, 0, 1, 90},
+		{"south", 1, 0, 0, 0, 180},
+		{"west", 0, 1, 0, 0, 270},
+		{"KDEN to KBJC", 39.8466, -104.6562, 39.9088, -105.1172, 280.1},
+	}
+	for _, tt := range tests {
+		got := Bearing(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+		if math.Abs(got-tt.want) > 0.1 {
+			t.Errorf("%s: Bearing() = %.1f, want %.1f", tt.name, got, tt.want)
+		}
+	}
+}
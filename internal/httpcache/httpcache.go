@@ -0,0 +1,267 @@
+// Package httpcache is an on-disk HTTP cache for GET requests.
+//
+// Responses are fresh for as long as their Cache-Control max-age or
+// Expires headers allow. Stale responses with an ETag or Last-Modified
+// validator are revalidated with a conditional request. When the network
+// is unreachable, or in offline mode, the last cached response is served
+// regardless of age and recorded so callers can warn that it is stale.
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotCached is returned in offline mode when a URL has no cached
+// response.
+type ErrNotCached struct {
+	URL string
+}
+
+func (e *ErrNotCached) Error() string {
+	return fmt.Sprintf("offline and no cached response for %s", e.URL)
+}
+
+// Transport is an http.RoundTripper that caches GET responses in Dir.
+type Transport struct {
+	Dir     string
+	Base    http.RoundTripper
+	Offline bool
+	Now     func() time.Time
+
+	mu    sync.Mutex
+	stale time.Time
+}
+
+// New returns a transport caching in dir over http.DefaultTransport.
+func New(dir string, offline bool) *Transport {
+	return &Transport{Dir: dir, Offline: offline}
+}
+
+// StaleSince returns the fetch time of the oldest stale response served
+// so far, and whether any stale response was served.
+func (t *Transport) StaleSince() (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stale, !t.stale.IsZero()
+}
+
+type entry struct {
+	URL      string      `json:"url"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	StoredAt time.Time   `json:"stored_at"`
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base().RoundTrip(req)
+	}
+
+	now := t.now()
+	key := t.key(req)
+	cached, _ := t.load(key)
+
+	if cached != nil && (t.Offline || now.Before(cached.freshUntil())) {
+		if !now.Before(cached.freshUntil()) {
+			t.markStale(cached.StoredAt)
+		}
+		return cached.response(req), nil
+	}
+	if t.Offline {
+		return nil, &ErrNotCached{URL: req.URL.String()}
+	}
+
+	out := req
+	if cached != nil {
+		out = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if lm := cached.Header.Get("Last-Modified"); lm != "" {
+			out.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	resp, err := t.base().RoundTrip(out)
+	if err != nil {
+		if cached != nil && req.Context().Err() == nil {
+			t.markStale(cached.StoredAt)
+			return cached.response(req), nil
+		}
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		for _, h := range []string{"Cache-Control", "Expires", "Date", "ETag", "Last-Modified"} {
+			if v := resp.Header.Get(h); v != "" {
+				cached.Header.Set(h, v)
+			}
+		}
+		cached.StoredAt = now
+		t.save(key, cached)
+		return cached.response(req), nil
+
+	case resp.StatusCode == http.StatusOK && !hasDirective(resp.Header, "no-store"):
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		e := &entry{
+			URL:      req.URL.String(),
+			Status:   resp.StatusCode,
+			Header:   resp.Header.Clone(),
+			Body:     body,
+			StoredAt: now,
+		}
+		t.save(key, e)
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return resp, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) now() time.Time {
+	if t.Now != nil {
+		return t.Now()
+	}
+	return time.Now()
+}
+
+func (t *Transport) markStale(storedAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stale.IsZero() || storedAt.Before(t.stale) {
+		t.stale = storedAt
+	}
+}
+
+func (t *Transport) key(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String() + "\n" + req.Header.Get("Accept")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *Transport) path(key string) string {
+	return filepath.Join(t.Dir, key[:2], key+".json")
+}
+
+func (t *Transport) load(key string) (*entry, error) {
+	data, err := os.ReadFile(t.path(key))
+	if err != nil {
+		return nil, err
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// save writes the entry atomically; cache write failures are ignored.
+func (t *Transport) save(key string, e *entry) {
+	path := t.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "tmp-*")
+	if err != nil {
+		return
+	}
+	w := bufio.NewWriter(tmp)
+	_, err = w.Write(data)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	os.Rename(tmp.Name(), path)
+}
+
+func (e *entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// freshUntil returns when the entry stops being fresh according to its
+// Cache-Control max-age (less any Age) or Expires header. Entries
+// without freshness information are immediately stale.
+func (e *entry) freshUntil() time.Time {
+	if hasDirective(e.Header, "no-cache") || hasDirective(e.Header, "no-store") {
+		return time.Time{}
+	}
+	if maxAge, ok := directive(e.Header, "max-age"); ok {
+		secs, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return time.Time{}
+		}
+		age, _ := strconv.Atoi(e.Header.Get("Age"))
+		return e.StoredAt.Add(time.Duration(secs-age) * time.Second)
+	}
+	if exp := e.Header.Get("Expires"); exp != "" {
+		expires, err := http.ParseTime(exp)
+		if err != nil {
+			return time.Time{}
+		}
+		// Measure against the server's clock when it sent a Date.
+		if date, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+			return e.StoredAt.Add(expires.Sub(date))
+		}
+		return expires
+	}
+	return time.Time{}
+}
+
+func directive(h http.Header, name string) (string, bool) {
+	for _, part := range strings.Split(h.Get("Cache-Control"), ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(k, name) {
+			return strings.Trim(v, `"`), true
+		}
+	}
+	return "", false
+}
+
+func hasDirective(h http.Header, name string) bool {
+	_, ok := directive(h, name)
+	return ok
+}
@@ -0,0 +1,221 @@
+package httpcache
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newTestTransport(t *testing.T) (*Transport, *clock) {
+	c := &clock{t: time.Date(2026, 2, 17, 10, 0, 0, 0, time.UTC)}
+	return &Transport{Dir: t.TempDir(), Now: c.now}, c
+}
+
+func get(t *testing.T, tr *Transport, url string) (*http.Response, string) {
+	t.Helper()
+	client := &http.Client{Transport: tr}
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestTransport_MaxAge(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	tr, clk := newTestTransport(t)
+	if _, body := get(t, tr, server.URL); body != "hello" {
+		t.Fatalf("body = %q", body)
+	}
+	if _, body := get(t, tr, server.URL); body != "hello" || calls.Load() != 1 {
+		t.Errorf("fresh response not served from cache (calls = %d)", calls.Load())
+	}
+
+	clk.t = clk.t.Add(2 * time.Minute)
+	get(t, tr, server.URL)
+	if calls.Load() != 2 {
+		t.Errorf("stale response not refetched (calls = %d)", calls.Load())
+	}
+	if _, stale := tr.StaleSince(); stale {
+		t.Error("StaleSince() reported stale data when online")
+	}
+}
+
+func TestTransport_ETagRevalidation(t *testing.T) {
+	var calls, conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("payload"))
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport(t)
+	get(t, tr, server.URL)
+	resp, body := get(t, tr, server.URL)
+	if calls.Load() != 2 || conditional.Load() != 1 {
+		t.Errorf("calls = %d, conditional = %d, want 2 and 1", calls.Load(), conditional.Load())
+	}
+	if resp.StatusCode != http.StatusOK || body != "payload" {
+		t.Errorf("revalidated response = %d %q, want 200 payload", resp.StatusCode, body)
+	}
+}
+
+func TestTransport_LastModifiedRevalidation(t *testing.T) {
+	lm := "Tue, 17 Feb 2026 09:00:00 GMT"
+	var conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lm {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lm)
+		w.Write([]byte("payload"))
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport(t)
+	get(t, tr, server.URL)
+	if _, body := get(t, tr, server.URL); body != "payload" || conditional.Load() != 1 {
+		t.Errorf("body = %q, conditional = %d", body, conditional.Load())
+	}
+}
+
+func TestTransport_NoStore(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write([]byte("secret"))
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport(t)
+	get(t, tr, server.URL)
+	get(t, tr, server.URL)
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+	tr.Offline = true
+	if _, err := (&http.Client{Transport: tr}).Get(server.URL); err == nil {
+		t.Error("no-store response should not be available offline")
+	}
+}
+
+func TestTransport_NetworkFailureServesStale(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("cached"))
+	}))
+
+	tr, clk := newTestTransport(t)
+	fetched := clk.t
+	get(t, tr, server.URL)
+	server.Close()
+
+	clk.t = clk.t.Add(time.Hour)
+	if _, body := get(t, tr, server.URL); body != "cached" {
+		t.Errorf("body = %q, want stale cached body", body)
+	}
+	since, stale := tr.StaleSince()
+	if !stale || !since.Equal(fetched) {
+		t.Errorf("StaleSince() = %v, %v, want %v, true", since, stale, fetched)
+	}
+}
+
+func TestTransport_Offline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport(t)
+	get(t, tr, server.URL+"/a")
+
+	tr.Offline = true
+	if _, body := get(t, tr, server.URL+"/a"); body != "data" {
+		t.Errorf("offline body = %q", body)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("offline mode made a network request")
+	}
+	if _, stale := tr.StaleSince(); !stale {
+		t.Error("StaleSince() should report stale data served offline")
+	}
+
+	_, err := (&http.Client{Transport: tr}).Get(server.URL + "/b")
+	var notCached *ErrNotCached
+	if !errors.As(err, &notCached) {
+		t.Errorf("error = %v, want ErrNotCached", err)
+	}
+}
+
+func TestTransport_ErrorsNotCached(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport(t)
+	get(t, tr, server.URL)
+	resp, _ := get(t, tr, server.URL)
+	if calls.Load() != 2 || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("calls = %d, status = %d", calls.Load(), resp.StatusCode)
+	}
+}
+
+func TestEntry_FreshUntil(t *testing.T) {
+	stored := time.Date(2026, 2, 17, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Time
+	}{
+		{"none", http.Header{}, time.Time{}},
+		{"max-age", http.Header{"Cache-Control": {"public, max-age=300"}}, stored.Add(5 * time.Minute)},
+		{"max-age with age", http.Header{"Cache-Control": {"max-age=300"}, "Age": {"100"}}, stored.Add(200 * time.Second)},
+		{"no-cache", http.Header{"Cache-Control": {"no-cache, max-age=300"}}, time.Time{}},
+		{"expires with date", http.Header{
+			"Expires": {"Tue, 17 Feb 2026 09:30:00 GMT"},
+			"Date":    {"Tue, 17 Feb 2026 09:00:00 GMT"},
+		}, stored.Add(30 * time.Minute)},
+		{"expires", http.Header{"Expires": {"Tue, 17 Feb 2026 11:00:00 GMT"}}, stored.Add(time.Hour)},
+		{"bad expires", http.Header{"Expires": {"0"}}, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &entry{Header: tt.header, StoredAt: stored}
+			if got := e.freshUntil(); !got.Equal(tt.want) {
+				t.Errorf("freshUntil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package metar
+
+import (
+	"fmt"
+	"strings"
+
+	"lastwind/internal/nws"
+	"lastwind/internal/units"
+)
+
+// Line is a report group with a plain-language description of it.
+type Line struct {
+	Raw     string
+	Meaning string
+}
+
+// Explain describes each group of the report in order, converting values
+// to sys. Groups that weren't decoded have an empty Meaning.
+func (r Report) Explain(sys units.System) []Line {
+	lines := make([]Line, len(r.Groups))
+	for i, g := range r.Groups {
+		lines[i] = Line{Raw: g.Raw, Meaning: r.describe(g, sys)}
+	}
+	return lines
+}
+
+func (r Report) describe(g Group, sys units.System) string {
+	deg := sys.Temperature.Label()
+	speed := func(kmh float64) string { return sys.Speed.Format(kmh) + " " + sys.Speed.Label() }
+	height := func(m float64) string {
+		_, unit := sys.Distance.Height(m)
+		return sys.Distance.FormatHeight(m) + " " + unit
+	}
+
+	switch g.Kind {
+	case KindType:
+		if r.Type == "SPECI" {
+			return "Special report"
+		}
+		return "Routine report"
+	case KindStation:
+		return "Station " + r.Station
+	case KindTime:
+		return fmt.Sprintf("Day %d, %02d:%02d UTC", r.Day, r.Hour, r.Minute)
+	case KindModifier:
+		if g.Raw == "COR" {
+			return "Corrected report"
+		}
+		return "Automated report"
+	case KindWind:
+		w := r.Wind
+		if w.Calm() {
+			return "Wind calm"
+		}
+		s := "Wind variable"
+		if w.Direction != nil {
+			s = fmt.Sprintf("Wind from %03.0f° (%s)", *w.Direction, nws.CompassDir(w.Direction))
+		}
+		s += " at " + speed(w.Speed)
+		if w.Gust != nil {
+			s += ", gusting " + speed(*w.Gust)
+		}
+		return s
+	case KindWindVariation:
+		return fmt.Sprintf("Direction varying %03.0f° to %03.0f°", *r.Wind.VariableFrom, *r.Wind.VariableTo)
+	case KindVisibility:
+		v := r.Visibility
+		s := "Visibility "
+		switch {
+		case v.LessThan:
+			s += "less than "
+		case v.MoreThan:
+			s += "at least "
+		}
+		return s + sys.Distance.Format(v.Distance) + " " + sys.Distance.Label()
+	case KindCAVOK:
+		return "Ceiling and visibility OK"
+	case KindNoSignificantWeather:
+		return "No significant weather"
+	case KindRVR:
+		v := r.RVR[g.Index]
+		s := "Runway " + v.Runway + " visual range "
+		if v.LessThan {
+			s += "less than "
+		}
+		if v.Max != nil {
+			s += sys.Distance.FormatHeight(v.Min) + " to "
+			if v.MoreThan {
+				s += "more than "
+			}
+			s += height(*v.Max)
+		} else {
+			if v.MoreThan {
+				s += "more than "
+			}
+			s += height(v.Min)
+		}
+		switch v.Trend {
+		case "U":
+			s += ", rising"
+		case "D":
+			s += ", falling"
+		case "N":
+			s += ", steady"
+		}
+		return s
+	case KindWeather:
+		return capitalize(r.Weather[g.Index].Description())
+	case KindSky:
+		l := r.Sky[g.Index]
+		s := coverNames[l.Cover]
+		if l.Base != nil {
+			s += " at " + height(*l.Base)
+		}
+		switch l.Cloud {
+		case "CB":
+			s += " (cumulonimbus)"
+		case "TCU":
+			s += " (towering cumulus)"
+		}
+		return s
+	case KindTemperature:
+		s := "Temperature " + sys.Temperature.Format(*r.Temperature) + deg
+		if r.Dewpoint != nil {
+			s += ", dewpoint " + sys.Temperature.Format(*r.Dewpoint) + deg
+		}
+		return s
+	case KindAltimeter:
+		return "Altimeter " + sys.Pressure.Format(*r.Altimeter) + " " + sys.Pressure.Label()
+	case KindRemarks:
+		return "Remarks"
+	case KindStationType:
+		if r.Remarks.StationType == "AO2" {
+			return "Automated station with precipitation sensor"
+		}
+		return "Automated station without precipitation sensor"
+	case KindPeakWind:
+		pk := r.Remarks.PeakWind
+		at := fmt.Sprintf("%02d:%02d UTC", pk.Hour, pk.Minute)
+		if pk.Hour < 0 {
+			at = fmt.Sprintf("minute %02d", pk.Minute)
+		}
+		return fmt.Sprintf("Peak wind %s from %03.0f° (%s) at %s", speed(pk.Speed), pk.Direction, nws.CompassDir(&pk.Direction), at)
+	case KindSeaLevelPressure:
+		return fmt.Sprintf("Sea-level pressure %.1f hPa", *r.Remarks.SeaLevelPressure/100)
+	case KindHourlyPrecipitation:
+		if r.Remarks.PrecipitationTrace {
+			return "Precipitation in the last hour: trace"
+		}
+		_, unit := sys.Distance.Precipitation(*r.Remarks.HourlyPrecipitation)
+		return "Precipitation in the last hour: " + sys.Distance.FormatPrecipitation(*r.Remarks.HourlyPrecipitation) + " " + unit
+	case KindTemperatureTenths:
+		s := fmt.Sprintf("Temperature %.1f%s", sys.Temperature.Convert(*r.Remarks.Temperature), deg)
+		if r.Remarks.Dewpoint != nil {
+			s += fmt.Sprintf(", dewpoint %.1f%s", sys.Temperature.Convert(*r.Remarks.Dewpoint), deg)
+		}
+		return s
+	}
+	return ""
+}
+
+var coverNames = map[string]string{
+	"SKC": "Sky clear", "CLR": "No clouds below 12,000 ft", "NSC": "No significant cloud",
+	"NCD": "No cloud detected", "FEW": "Few clouds", "SCT": "Scattered clouds",
+	"BKN": "Broken clouds", "OVC": "Overcast", "VV": "Vertical visibility",
+}
+
+var descriptorNames = map[string]string{
+	"MI": "shallow", "PR": "partial", "BC": "patches of", "DR": "low drifting",
+	"BL": "blowing", "FZ": "freezing",
+}
+
+var phenomenonNames = map[string]string{
+	"DZ": "drizzle", "RA": "rain", "SN": "snow", "SG": "snow grains", "IC": "ice crystals",
+	"PL": "ice pellets", "GR": "hail", "GS": "small hail", "UP": "unknown precipitation",
+	"BR": "mist", "FG": "fog", "FU": "smoke", "VA": "volcanic ash", "DU": "dust",
+	"SA": "sand", "HZ": "haze", "PY": "spray", "PO": "dust whirls", "SQ": "squalls",
+	"FC": "funnel cloud", "SS": "sandstorm", "DS": "duststorm",
+}
+
+// Description describes the weather in words, e.g. "light rain showers"
+// for -SHRA or "thunderstorm with heavy rain" for +TSRA.
+func (w Weather) Description() string {
+	names := make([]string, len(w.Phenomena))
+	for i, p := range w.Phenomena {
+		names[i] = phenomenonNames[p]
+		if p == "FC" && w.Intensity == "+" {
+			return "tornado or waterspout"
+		}
+	}
+	what := strings.Join(names, " and ")
+
+	intensity := map[string]string{"-": "light ", "+": "heavy "}[w.Intensity]
+	var s string
+	switch w.Descriptor {
+	case "TS":
+		s = "thunderstorm"
+		if what != "" {
+			s += " with " + intensity + what
+		}
+	case "SH":
+		s = "showers"
+		if what != "" {
+			s = intensity + what + " showers"
+		}
+	case "":
+		s = intensity + what
+	default:
+		s = intensity + descriptorNames[w.Descriptor]
+		if what != "" {
+			s += " " + what
+		}
+	}
+	if w.Intensity == "VC" {
+		s += " in the vicinity"
+	}
+	return s
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
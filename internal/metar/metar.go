@@ -0,0 +1,437 @@
+// Package metar decodes METAR and SPECI aviation weather reports, such as
+// the rawMessage of an NWS observation, and TAF aerodrome forecasts.
+//
+// Decoded values use the same units as nws.Observation: °C, km/h, Pa,
+// metres and millimetres.
+package metar
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Report is a decoded METAR. Fields are nil or empty when the report
+// doesn't include them.
+type Report struct {
+	Raw       string
+	Type      string // METAR or SPECI
+	Station   string
+	Day       int
+	Hour      int
+	Minute    int
+	Auto      bool
+	Corrected bool
+
+	Wind       *Wind
+	Visibility *Visibility
+	RVR        []RVR
+	Weather    []Weather
+	Sky        []SkyLayer
+	CAVOK      bool
+	// NoSignificantWeather is a TAF's NSW: the forecast weather ends.
+	NoSignificantWeather bool
+
+	Temperature *float64
+	Dewpoint    *float64
+	Altimeter   *float64
+
+	Remarks Remarks
+
+	// Groups lists every group in the report in order, with what it was
+	// decoded as.
+	Groups []Group
+}
+
+// Wind is the reported surface wind. Direction is nil when the wind is
+// variable (VRB); VariableFrom and VariableTo give a dddVddd range.
+type Wind struct {
+	Direction    *float64
+	Speed        float64
+	Gust         *float64
+	VariableFrom *float64
+	VariableTo   *float64
+}
+
+// Calm reports whether the wind is calm (00000KT).
+func (w Wind) Calm() bool {
+	return w.Speed == 0 && w.Gust == nil
+}
+
+// Visibility is the prevailing visibility in metres. LessThan and
+// MoreThan mark M and P prefixes (or 9999, ten kilometres or more).
+type Visibility struct {
+	Distance float64
+	LessThan bool
+	MoreThan bool
+}
+
+// RVR is a runway visual range in metres. Max is set for a varying range;
+// Trend is U (rising), D (falling), N (no change) or empty.
+type RVR struct {
+	Runway   string
+	Min      float64
+	Max      *float64
+	LessThan bool
+	MoreThan bool
+	Trend    string
+}
+
+// Weather is a present weather group such as "-SHRA": Intensity is "-",
+// "+", "VC" or empty, Descriptor a code such as SH or FZ, and Phenomena
+// codes such as RA or BR.
+type Weather struct {
+	Raw        string
+	Intensity  string
+	Descriptor string
+	Phenomena  []string
+}
+
+// SkyLayer is a cloud layer such as "BKN040CB". Cover is SKC, CLR, NSC,
+// NCD, FEW, SCT, BKN, OVC or VV (vertical visibility); Base is in metres
+// and Cloud is CB or TCU for convective cloud.
+type SkyLayer struct {
+	Raw   string
+	Cover string
+	Base  *float64
+	Cloud string
+}
+
+// Remarks holds the decoded RMK section.
+type Remarks struct {
+	StationType         string // AO1 or AO2
+	PeakWind            *PeakWind
+	SeaLevelPressure    *float64
+	HourlyPrecipitation *float64
+	PrecipitationTrace  bool
+	Temperature         *float64
+	Dewpoint            *float64
+	// Other holds remarks that aren't decoded, as reported.
+	Other []string
+}
+
+// PeakWind is the PK WND remark: the highest gust since the last report.
+type PeakWind struct {
+	Direction float64
+	Speed     float64
+	Hour      int // -1 when the remark gives only minutes
+	Minute    int
+}
+
+// Kind identifies what a group was decoded as.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindType
+	KindStation
+	KindTime
+	KindModifier
+	KindWind
+	KindWindVariation
+	KindVisibility
+	KindCAVOK
+	KindNoSignificantWeather
+	KindRVR
+	KindWeather
+	KindSky
+	KindTemperature
+	KindAltimeter
+	KindRemarks
+	KindStationType
+	KindPeakWind
+	KindSeaLevelPressure
+	KindHourlyPrecipitation
+	KindTemperatureTenths
+	KindRemark
+)
+
+// Group is one group of a report. Index is its position among the
+// report's RVR, Weather or Sky entries for those kinds.
+type Group struct {
+	Raw   string
+	Kind  Kind
+	Index int
+}
+
+var (
+	timeRe       = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	windRe       = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(?:G(\d{2,3}))?(KT|MPS|KMH)$`)
+	variationRe  = regexp.MustCompile(`^(\d{3})V(\d{3})$`)
+	visSMRe      = regexp.MustCompile(`^([MP])?(?:(\d+)|(\d+)/(\d+))SM$`)
+	visWholeRe   = regexp.MustCompile(`^\d$`)
+	visFracRe    = regexp.MustCompile(`^(\d)/(\d{1,2})SM$`)
+	visMetresRe  = regexp.MustCompile(`^\d{4}$`)
+	rvrRe        = regexp.MustCompile(`^R(\d{2}[LCR]?)/([MP])?(\d{4})(?:V([MP])?(\d{4}))?(FT)?/?([UDN])?$`)
+	weatherRe    = regexp.MustCompile(`^(-|\+|VC)?(MI|PR|BC|DR|BL|SH|TS|FZ)?((?:DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)*)$`)
+	skyRe        = regexp.MustCompile(`^(FEW|SCT|BKN|OVC|VV)(\d{3}|///)(CB|TCU)?$`)
+	tempRe       = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	altimeterRe  = regexp.MustCompile(`^([AQ])(\d{4})$`)
+	peakWindRe   = regexp.MustCompile(`^(\d{3})(\d{2,3})/(\d{2})?(\d{2})$`)
+	slpRe        = regexp.MustCompile(`^SLP(\d{3})$`)
+	hourlyPcpRe  = regexp.MustCompile(`^P(\d{4})$`)
+	tempTenthsRe = regexp.MustCompile(`^T([01])(\d{3})(?:([01])(\d{3}))?$`)
+)
+
+// Parse decodes a METAR or SPECI report. Groups it doesn't recognise are
+// kept in Groups as KindUnknown; it fails only when the report has no
+// station identifier.
+func Parse(raw string) (Report, error) {
+	r := Report{Raw: strings.TrimSpace(raw)}
+	tokens := strings.Fields(strings.TrimSuffix(r.Raw, "="))
+	add := func(raw string, kind Kind, index int) {
+		r.Groups = append(r.Groups, Group{Raw: raw, Kind: kind, Index: index})
+	}
+
+	i := 0
+	if i < len(tokens) && (tokens[i] == "METAR" || tokens[i] == "SPECI") {
+		r.Type = tokens[i]
+		add(tokens[i], KindType, 0)
+		i++
+	}
+	if i >= len(tokens) || !isStation(tokens[i]) {
+		return r, fmt.Errorf("metar: missing station identifier in %q", raw)
+	}
+	r.Station = tokens[i]
+	add(tokens[i], KindStation, 0)
+	i++
+
+	rest := r.parseGroups(tokens[i:])
+	if len(rest) > 0 {
+		add(rest[0], KindRemarks, 0)
+		r.parseRemarks(rest[1:])
+	}
+	return r, nil
+}
+
+// parseGroups decodes the body groups of a report up to RMK, and returns
+// the remaining tokens starting with RMK.
+func (r *Report) parseGroups(tokens []string) []string {
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok == "RMK" {
+			return tokens[i:]
+		}
+
+		// Visibility such as "1 1/2SM" spans two groups.
+		if visWholeRe.MatchString(tok) && i+1 < len(tokens) {
+			if m := visFracRe.FindStringSubmatch(tokens[i+1]); m != nil {
+				miles := atof(tok) + atof(m[1])/atof(m[2])
+				r.Visibility = &Visibility{Distance: miles * metresPerMile}
+				r.Groups = append(r.Groups, Group{Raw: tok + " " + tokens[i+1], Kind: KindVisibility})
+				i++
+				continue
+			}
+		}
+
+		kind, index := r.parseGroup(tok)
+		r.Groups = append(r.Groups, Group{Raw: tok, Kind: kind, Index: index})
+	}
+	return nil
+}
+
+const (
+	metresPerMile = 1609.344
+	metresPerFoot = 0.3048
+	paPerInHg     = 3386.389
+)
+
+func (r *Report) parseGroup(tok string) (Kind, int) {
+	if m := timeRe.FindStringSubmatch(tok); m != nil && r.Day == 0 {
+		r.Day, r.Hour, r.Minute = atoi(m[1]), atoi(m[2]), atoi(m[3])
+		return KindTime, 0
+	}
+	switch tok {
+	case "AUTO":
+		r.Auto = true
+		return KindModifier, 0
+	case "COR":
+		r.Corrected = true
+		return KindModifier, 0
+	case "CAVOK":
+		r.CAVOK = true
+		return KindCAVOK, 0
+	case "NSW":
+		r.NoSignificantWeather = true
+		return KindNoSignificantWeather, 0
+	case "SKC", "CLR", "NSC", "NCD":
+		r.Sky = append(r.Sky, SkyLayer{Raw: tok, Cover: tok})
+		return KindSky, len(r.Sky) - 1
+	}
+
+	if m := windRe.FindStringSubmatch(tok); m != nil {
+		scale := map[string]float64{"KT": 1.852, "MPS": 3.6, "KMH": 1}[m[4]]
+		w := &Wind{Speed: atof(m[2]) * scale}
+		if m[1] != "VRB" {
+			d := atof(m[1])
+			w.Direction = &d
+		}
+		if m[3] != "" {
+			g := atof(m[3]) * scale
+			w.Gust = &g
+		}
+		r.Wind = w
+		return KindWind, 0
+	}
+	if m := variationRe.FindStringSubmatch(tok); m != nil && r.Wind != nil {
+		from, to := atof(m[1]), atof(m[2])
+		r.Wind.VariableFrom, r.Wind.VariableTo = &from, &to
+		return KindWindVariation, 0
+	}
+	if m := visSMRe.FindStringSubmatch(tok); m != nil {
+		miles := atof(m[2])
+		if m[3] != "" {
+			miles = atof(m[3]) / atof(m[4])
+		}
+		r.Visibility = &Visibility{Distance: miles * metresPerMile, LessThan: m[1] == "M", MoreThan: m[1] == "P"}
+		return KindVisibility, 0
+	}
+	if visMetresRe.MatchString(tok) && r.Visibility == nil {
+		r.Visibility = &Visibility{Distance: atof(tok), MoreThan: tok == "9999"}
+		return KindVisibility, 0
+	}
+	if m := rvrRe.FindStringSubmatch(tok); m != nil {
+		scale := 1.0
+		if m[6] == "FT" {
+			scale = metresPerFoot
+		}
+		v := RVR{Runway: m[1], Min: atof(m[3]) * scale, LessThan: m[2] == "M", MoreThan: m[2] == "P" || m[4] == "P", Trend: m[7]}
+		if m[5] != "" {
+			max := atof(m[5]) * scale
+			v.Max = &max
+		}
+		r.RVR = append(r.RVR, v)
+		return KindRVR, len(r.RVR) - 1
+	}
+	if m := skyRe.FindStringSubmatch(tok); m != nil {
+		l := SkyLayer{Raw: tok, Cover: m[1], Cloud: m[3]}
+		if m[2] != "///" {
+			base := atof(m[2]) * 100 * metresPerFoot
+			l.Base = &base
+		}
+		r.Sky = append(r.Sky, l)
+		return KindSky, len(r.Sky) - 1
+	}
+	if m := tempRe.FindStringSubmatch(tok); m != nil {
+		t := signed(m[1])
+		r.Temperature = &t
+		if m[2] != "" {
+			d := signed(m[2])
+			r.Dewpoint = &d
+		}
+		return KindTemperature, 0
+	}
+	if m := altimeterRe.FindStringSubmatch(tok); m != nil {
+		pa := atof(m[2]) * 100 // Q: hPa
+		if m[1] == "A" {
+			pa = atof(m[2]) / 100 * paPerInHg
+		}
+		r.Altimeter = &pa
+		return KindAltimeter, 0
+	}
+	if m := weatherRe.FindStringSubmatch(tok); m != nil && (m[2] != "" || m[3] != "") {
+		w := Weather{Raw: tok, Intensity: m[1], Descriptor: m[2]}
+		for p := m[3]; p != ""; p = p[2:] {
+			w.Phenomena = append(w.Phenomena, p[:2])
+		}
+		r.Weather = append(r.Weather, w)
+		return KindWeather, len(r.Weather) - 1
+	}
+	return KindUnknown, 0
+}
+
+func (r *Report) parseRemarks(tokens []string) {
+	add := func(raw string, kind Kind) {
+		r.Groups = append(r.Groups, Group{Raw: raw, Kind: kind})
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok == "PK" && i+2 < len(tokens) && tokens[i+1] == "WND" {
+			if m := peakWindRe.FindStringSubmatch(tokens[i+2]); m != nil {
+				pk := &PeakWind{Direction: atof(m[1]), Speed: atof(m[2]) * 1.852, Hour: -1, Minute: atoi(m[4])}
+				if m[3] != "" {
+					pk.Hour = atoi(m[3])
+				}
+				r.Remarks.PeakWind = pk
+				add(strings.Join(tokens[i:i+3], " "), KindPeakWind)
+				i += 2
+				continue
+			}
+		}
+
+		switch {
+		case tok == "AO1" || tok == "AO2":
+			r.Remarks.StationType = tok
+			add(tok, KindStationType)
+		case slpRe.MatchString(tok):
+			hpa := atof(tok[3:]) / 10
+			if hpa < 50 {
+				hpa += 1000
+			} else {
+				hpa += 900
+			}
+			pa := hpa * 100
+			r.Remarks.SeaLevelPressure = &pa
+			add(tok, KindSeaLevelPressure)
+		case hourlyPcpRe.MatchString(tok):
+			mm := atof(tok[1:]) / 100 * 25.4
+			r.Remarks.HourlyPrecipitation = &mm
+			r.Remarks.PrecipitationTrace = mm == 0
+			add(tok, KindHourlyPrecipitation)
+		case tempTenthsRe.MatchString(tok):
+			m := tempTenthsRe.FindStringSubmatch(tok)
+			t := tenths(m[1], m[2])
+			r.Remarks.Temperature = &t
+			if m[3] != "" {
+				d := tenths(m[3], m[4])
+				r.Remarks.Dewpoint = &d
+			}
+			add(tok, KindTemperatureTenths)
+		default:
+			r.Remarks.Other = append(r.Remarks.Other, tok)
+			add(tok, KindRemark)
+		}
+	}
+}
+
+func isStation(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return s[0] >= 'A' && s[0] <= 'Z'
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func atof(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// signed parses a METAR temperature such as "M05" (-5).
+func signed(s string) float64 {
+	if strings.HasPrefix(s, "M") {
+		return -atof(s[1:])
+	}
+	return atof(s)
+}
+
+// tenths parses a T-group temperature: a sign digit (1 for negative) and
+// three digits of tenths of a degree.
+func tenths(sign, digits string) float64 {
+	v := atof(digits) / 10
+	if sign == "1" {
+		return -v
+	}
+	return v
+}
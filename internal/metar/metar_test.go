@@ -0,0 +1,189 @@
+package metar
+
+import (
+	"math"
+	"testing"
+
+	"lastwind/internal/units"
+)
+
+func near(got *float64, want float64) bool {
+	return got != nil && math.Abs(*got-want) < 0.05
+}
+
+func TestParse(t *testing.T) {
+	r, err := Parse("METAR KDEN 171753Z AUTO 27015G25KT 240V300 1 1/2SM R16L/2400FT -SN BR FEW015 BKN040CB M02/M05 A2992 RMK AO2 PK WND 28045/1715 SLP142 P0003 T10171050")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if r.Type != "METAR" || r.Station != "KDEN" || r.Day != 17 || r.Hour != 17 || r.Minute != 53 || !r.Auto {
+		t.Errorf("header = %q %q %d %02d:%02d auto=%v", r.Type, r.Station, r.Day, r.Hour, r.Minute, r.Auto)
+	}
+
+	w := r.Wind
+	if w == nil || !near(w.Direction, 270) || math.Abs(w.Speed-27.78) > 0.01 || !near(w.Gust, 46.3) {
+		t.Fatalf("wind = %+v", w)
+	}
+	if !near(w.VariableFrom, 240) || !near(w.VariableTo, 300) {
+		t.Errorf("variable wind = %v–%v", w.VariableFrom, w.VariableTo)
+	}
+	if v := r.Visibility; v == nil || math.Abs(v.Distance-2414.0) > 0.1 {
+		t.Errorf("visibility = %+v", v)
+	}
+	if len(r.RVR) != 1 || r.RVR[0].Runway != "16L" || math.Abs(r.RVR[0].Min-731.52) > 0.01 {
+		t.Errorf("RVR = %+v", r.RVR)
+	}
+	if len(r.Weather) != 2 || r.Weather[0].Intensity != "-" || r.Weather[0].Phenomena[0] != "SN" || r.Weather[1].Raw != "BR" {
+		t.Errorf("weather = %+v", r.Weather)
+	}
+	if len(r.Sky) != 2 || r.Sky[1].Cover != "BKN" || !near(r.Sky[1].Base, 1219.2) || r.Sky[1].Cloud != "CB" {
+		t.Errorf("sky = %+v", r.Sky)
+	}
+	if !near(r.Temperature, -2) || !near(r.Dewpoint, -5) {
+		t.Errorf("temperature = %v, dewpoint = %v", r.Temperature, r.Dewpoint)
+	}
+	if !near(r.Altimeter, 101320.8) {
+		t.Errorf("altimeter = %v", *r.Altimeter)
+	}
+
+	rmk := r.Remarks
+	if rmk.StationType != "AO2" {
+		t.Errorf("station type = %q", rmk.StationType)
+	}
+	if pk := rmk.PeakWind; pk == nil || pk.Direction != 280 || math.Abs(pk.Speed-83.34) > 0.01 || pk.Hour != 17 || pk.Minute != 15 {
+		t.Errorf("peak wind = %+v", pk)
+	}
+	if !near(rmk.SeaLevelPressure, 101420) {
+		t.Errorf("sea-level pressure = %v", rmk.SeaLevelPressure)
+	}
+	if !near(rmk.HourlyPrecipitation, 0.762) || rmk.PrecipitationTrace {
+		t.Errorf("hourly precipitation = %v trace=%v", rmk.HourlyPrecipitation, rmk.PrecipitationTrace)
+	}
+	if !near(rmk.Temperature, -1.7) || !near(rmk.Dewpoint, -5.0) {
+		t.Errorf("T group = %v/%v", rmk.Temperature, rmk.Dewpoint)
+	}
+
+	if len(r.Groups) != 20 || r.Groups[6].Raw != "1 1/2SM" || r.Groups[14].Kind != KindRemarks {
+		t.Errorf("groups = %+v", r.Groups)
+	}
+}
+
+func TestParse_Groups(t *testing.T) {
+	tests := []struct {
+		raw   string
+		check func(Report) bool
+	}{
+		{"KBJC 171753Z 00000KT 10SM CLR 05/M03 A3001", func(r Report) bool {
+			return r.Wind.Calm() && r.Visibility.Distance > 16000 && r.Sky[0].Cover == "CLR"
+		}},
+		{"KBJC 171753Z VRB03KT P6SM SKC", func(r Report) bool {
+			return r.Wind.Direction == nil && math.Abs(r.Wind.Speed-5.556) < 0.01 && r.Visibility.MoreThan
+		}},
+		{"KBJC 171753Z 18005KT M1/4SM FG VV002 00/00", func(r Report) bool {
+			return r.Visibility.LessThan && math.Abs(r.Visibility.Distance-402.3) < 0.1 &&
+				r.Sky[0].Cover == "VV" && near(r.Sky[0].Base, 60.96)
+		}},
+		{"EGLL 171750Z 24012MPS 9999 SCT030 12/08 Q1013", func(r Report) bool {
+			return math.Abs(r.Wind.Speed-43.2) < 0.01 && r.Visibility.MoreThan && near(r.Altimeter, 101300)
+		}},
+		{"EGLL 171750Z 24005KT CAVOK 12/08 Q1013", func(r Report) bool {
+			return r.CAVOK && r.Visibility == nil
+		}},
+		{"KDEN 171753Z 27010KT 1/2SM R34L/1000V1800FT/U +TSRA OVC010", func(r Report) bool {
+			v := r.RVR[0]
+			return v.Runway == "34L" && math.Abs(v.Min-304.8) < 0.01 && near(v.Max, 548.64) && v.Trend == "U" &&
+				r.Weather[0].Descriptor == "TS" && r.Weather[0].Intensity == "+"
+		}},
+		{"KDEN 171753Z 27010KT 10SM VCSH FZFG BKN///", func(r Report) bool {
+			return r.Weather[0].Intensity == "VC" && r.Weather[0].Descriptor == "SH" &&
+				r.Weather[1].Descriptor == "FZ" && r.Sky[0].Base == nil
+		}},
+		{"SPECI KDEN 171802Z COR 27010KT 10SM M05/ A2992 RMK AO1 PK WND 27032/05 SLP982 P0000 T1050", func(r Report) bool {
+			pk := r.Remarks.PeakWind
+			return r.Type == "SPECI" && r.Corrected && r.Dewpoint == nil && r.Remarks.StationType == "AO1" &&
+				pk != nil && pk.Hour == -1 && pk.Minute == 5 && near(r.Remarks.SeaLevelPressure, 99820) &&
+				r.Remarks.PrecipitationTrace && near(r.Remarks.Temperature, -5) && r.Remarks.Dewpoint == nil
+		}},
+		{"KDEN 171753Z 27010KT 10SM XYZ CLR RMK SNB12 $", func(r Report) bool {
+			return r.Groups[4].Kind == KindUnknown && len(r.Remarks.Other) == 2 && r.Remarks.Other[1] == "$"
+		}},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.raw)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.raw, err)
+			continue
+		}
+		if !tt.check(r) {
+			t.Errorf("Parse(%q) = %+v", tt.raw, r)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "METAR", "171753Z 27010KT"} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q): expected an error", raw)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	r, err := Parse("KDEN 171753Z 27015G25KT 240V300 3SM R16L/P6000FT -SHRA BKN040CB M02/M05 A2992 RMK AO2 PK WND 28045/1715 SLP142 P0000 T10171050")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Station KDEN",
+		"Day 17, 17:53 UTC",
+		"Wind from 270° (W) at 17 mph, gusting 29 mph",
+		"Direction varying 240° to 300°",
+		"Visibility 3.0 mi",
+		"Runway 16L visual range more than 6000 ft",
+		"Light rain showers",
+		"Broken clouds at 4000 ft (cumulonimbus)",
+		"Temperature 28°F, dewpoint 23°F",
+		"Altimeter 29.92 inHg",
+		"Remarks",
+		"Automated station with precipitation sensor",
+		"Peak wind 52 mph from 280° (W) at 17:15 UTC",
+		"Sea-level pressure 1014.2 hPa",
+		"Precipitation in the last hour: trace",
+		"Temperature 28.9°F, dewpoint 23.0°F",
+	}
+	lines := r.Explain(units.US)
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %+v", len(lines), len(want), lines)
+	}
+	for i, l := range lines {
+		if l.Meaning != want[i] {
+			t.Errorf("%s: got %q, want %q", l.Raw, l.Meaning, want[i])
+		}
+	}
+}
+
+func TestWeather_Description(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"-SN", "light snow"},
+		{"+TSRA", "thunderstorm with heavy rain"},
+		{"TS", "thunderstorm"},
+		{"-SHRASN", "light rain and snow showers"},
+		{"VCSH", "showers in the vicinity"},
+		{"FZFG", "freezing fog"},
+		{"BLSN", "blowing snow"},
+		{"+FC", "tornado or waterspout"},
+	}
+	for _, tt := range tests {
+		r, err := Parse("KDEN " + tt.raw)
+		if err != nil || len(r.Weather) != 1 {
+			t.Errorf("Parse(%q): %v %+v", tt.raw, err, r.Weather)
+			continue
+		}
+		if got := r.Weather[0].Description(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
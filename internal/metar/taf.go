@@ -0,0 +1,234 @@
+package metar
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TAF is a decoded terminal aerodrome forecast. Times are in UTC.
+type TAF struct {
+	Raw       string
+	Station   string
+	Amended   bool
+	Corrected bool
+	Issued    time.Time
+	ValidFrom time.Time
+	ValidTo   time.Time
+	// Segments are the initial conditions and each change group, ordered
+	// by start time.
+	Segments []Segment
+}
+
+// Change groups of a TAF segment.
+const (
+	ChangeInitial   = ""
+	ChangeFrom      = "FM"
+	ChangeBecoming  = "BECMG"
+	ChangeTemporary = "TEMPO"
+	ChangeProb      = "PROB"
+	ChangeProbTempo = "PROB TEMPO"
+)
+
+// Segment is a period of a TAF: the initial conditions, an FM group
+// (which replaces the forecast until the next one), or a BECMG, TEMPO or
+// PROB30/40 group (which modify it). Fields the group doesn't forecast
+// are nil or empty.
+type Segment struct {
+	Raw         string
+	Change      string
+	Probability int
+	From, To    time.Time
+
+	Wind                 *Wind
+	Visibility           *Visibility
+	Weather              []Weather
+	Sky                  []SkyLayer
+	CAVOK                bool
+	NoSignificantWeather bool
+}
+
+// Ceiling returns the base in metres of the lowest broken, overcast or
+// obscured layer, or nil if there is none.
+func (s Segment) Ceiling() *float64 {
+	var ceiling *float64
+	for _, l := range s.Sky {
+		switch l.Cover {
+		case "BKN", "OVC", "VV":
+			if l.Base != nil && (ceiling == nil || *l.Base < *ceiling) {
+				ceiling = l.Base
+			}
+		}
+	}
+	return ceiling
+}
+
+// SkyCondition returns the cloud layers as forecast, e.g. "SCT040
+// BKN100".
+func (s Segment) SkyCondition() string {
+	layers := make([]string, len(s.Sky))
+	for i, l := range s.Sky {
+		layers[i] = l.Raw
+	}
+	return strings.Join(layers, " ")
+}
+
+// WeatherCodes returns the forecast weather groups, e.g. "-SHRA BR", or
+// NSW when the weather is forecast to end.
+func (s Segment) WeatherCodes() string {
+	codes := make([]string, len(s.Weather))
+	for i, w := range s.Weather {
+		codes[i] = w.Raw
+	}
+	if s.NoSignificantWeather {
+		codes = append(codes, "NSW")
+	}
+	return strings.Join(codes, " ")
+}
+
+var (
+	periodRe = regexp.MustCompile(`^(\d{2})(\d{2})/(\d{2})(\d{2})$`)
+	fromRe   = regexp.MustCompile(`^FM(\d{2})(\d{2})(\d{2})$`)
+	probRe   = regexp.MustCompile(`^PROB(\d{2})$`)
+)
+
+// ParseTAF decodes a TAF from text such as a product's productText, which
+// may include the product header before the line starting "TAF". The
+// forecast's day-of-month times are resolved to the month of issued, the
+// product's issuance time.
+func ParseTAF(text string, issued time.Time) (TAF, error) {
+	if i := strings.Index(text, "="); i >= 0 {
+		text = text[:i]
+	}
+	if i := strings.Index(text, "$$"); i >= 0 {
+		text = text[:i]
+	}
+	tokens := strings.Fields(text)
+	for i, tok := range tokens {
+		if tok == "TAF" {
+			tokens = tokens[i+1:]
+			break
+		}
+	}
+
+	var t TAF
+	for len(tokens) > 0 && (tokens[0] == "AMD" || tokens[0] == "COR") {
+		t.Amended = t.Amended || tokens[0] == "AMD"
+		t.Corrected = t.Corrected || tokens[0] == "COR"
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 || !isStation(tokens[0]) {
+		return t, fmt.Errorf("taf: missing station identifier")
+	}
+	t.Station = tokens[0]
+	t.Raw = strings.Join(tokens, " ")
+	tokens = tokens[1:]
+
+	if len(tokens) > 0 {
+		if m := timeRe.FindStringSubmatch(tokens[0]); m != nil {
+			t.Issued = resolveDay(issued, atoi(m[1]), atoi(m[2]), atoi(m[3]))
+			issued = t.Issued
+			tokens = tokens[1:]
+		}
+	}
+	if len(tokens) == 0 || !periodRe.MatchString(tokens[0]) {
+		return t, fmt.Errorf("taf: missing valid period for %s", t.Station)
+	}
+	t.ValidFrom, t.ValidTo = parsePeriod(tokens[0], issued)
+	tokens = tokens[1:]
+
+	// Split the groups at each change indicator. A BECMG, TEMPO or PROB
+	// group without a period spans the FM group (or initial conditions)
+	// it falls in.
+	current := &Segment{From: t.ValidFrom}
+	enclosing := current
+	var segments []*Segment
+	var groups []string
+	unbounded := make(map[*Segment]*Segment)
+	finish := func() {
+		current.Raw = strings.Join(groups, " ")
+		var r Report
+		r.parseGroups(groups)
+		current.Wind, current.Visibility, current.Weather, current.Sky = r.Wind, r.Visibility, r.Weather, r.Sky
+		current.CAVOK, current.NoSignificantWeather = r.CAVOK, r.NoSignificantWeather
+		segments = append(segments, current)
+	}
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok == "RMK" {
+			break
+		}
+
+		next := &Segment{}
+		indicator := []string{tok}
+		switch m := fromRe.FindStringSubmatch(tok); {
+		case m != nil:
+			next.Change = ChangeFrom
+			next.From = resolveDay(issued, atoi(m[1]), atoi(m[2]), atoi(m[3]))
+			enclosing = next
+		case tok == "BECMG" || tok == "TEMPO" || probRe.MatchString(tok):
+			next.Change = tok
+			if m := probRe.FindStringSubmatch(tok); m != nil {
+				next.Change, next.Probability = ChangeProb, atoi(m[1])
+				if i+1 < len(tokens) && tokens[i+1] == "TEMPO" {
+					next.Change = ChangeProbTempo
+					i++
+					indicator = append(indicator, tokens[i])
+				}
+			}
+			if i+1 < len(tokens) && periodRe.MatchString(tokens[i+1]) {
+				i++
+				indicator = append(indicator, tokens[i])
+				next.From, next.To = parsePeriod(tokens[i], issued)
+			} else {
+				next.From = enclosing.From
+				unbounded[next] = enclosing
+			}
+		default:
+			groups = append(groups, tok)
+			continue
+		}
+		finish()
+		current, groups = next, indicator
+	}
+	finish()
+
+	// The initial conditions and each FM group last until the next FM.
+	end := t.ValidTo
+	for i := len(segments) - 1; i >= 0; i-- {
+		if s := segments[i]; s.Change == ChangeInitial || s.Change == ChangeFrom {
+			s.To, end = end, s.From
+		}
+	}
+	for s, e := range unbounded {
+		s.To = e.To
+	}
+
+	for _, s := range segments {
+		t.Segments = append(t.Segments, *s)
+	}
+	sort.SliceStable(t.Segments, func(i, j int) bool { return t.Segments[i].From.Before(t.Segments[j].From) })
+	return t, nil
+}
+
+// parsePeriod parses a ddhh/ddhh valid period.
+func parsePeriod(s string, ref time.Time) (time.Time, time.Time) {
+	m := periodRe.FindStringSubmatch(s)
+	return resolveDay(ref, atoi(m[1]), atoi(m[2]), 0), resolveDay(ref, atoi(m[3]), atoi(m[4]), 0)
+}
+
+// resolveDay returns the UTC time on a day of the month nearest ref.
+// Hour 24 is midnight at the end of the day.
+func resolveDay(ref time.Time, day, hour, minute int) time.Time {
+	ref = ref.UTC()
+	month := ref.Month()
+	switch {
+	case day-ref.Day() > 15:
+		month--
+	case ref.Day()-day > 15:
+		month++
+	}
+	return time.Date(ref.Year(), month, day, hour, minute, 0, 0, time.UTC)
+}
@@ -0,0 +1,125 @@
+package metar
+
+import (
+	"testing"
+	"time"
+)
+
+const tafText = `000
+FTUS45 KBOU 171720
+TAFDEN
+TAF
+KDEN 171720Z 1718/1824 27015G25KT P6SM SCT080 BKN200
+     TEMPO 1720/1724 VRB20G35KT 3SM TSRA BKN060CB
+     FM180200 30008KT P6SM FEW100
+     BECMG 1808/1810 02010KT 5SM -SN BKN015
+     PROB30 1812/1816 1SM SN OVC008
+     FM181800 36012KT P6SM NSW SCT050=
+$$
+`
+
+func TestParseTAF(t *testing.T) {
+	issued := time.Date(2024, 3, 17, 17, 22, 0, 0, time.UTC)
+	taf, err := ParseTAF(tafText, issued)
+	if err != nil {
+		t.Fatalf("ParseTAF: %v", err)
+	}
+	at := func(day, hour int) time.Time { return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC) }
+
+	if taf.Station != "KDEN" || !taf.Issued.Equal(time.Date(2024, 3, 17, 17, 20, 0, 0, time.UTC)) {
+		t.Errorf("header = %s issued %v", taf.Station, taf.Issued)
+	}
+	if !taf.ValidFrom.Equal(at(17, 18)) || !taf.ValidTo.Equal(at(19, 0)) {
+		t.Errorf("valid = %v to %v", taf.ValidFrom, taf.ValidTo)
+	}
+
+	want := []struct {
+		change   string
+		prob     int
+		from, to time.Time
+	}{
+		{ChangeInitial, 0, at(17, 18), at(18, 2)},
+		{ChangeTemporary, 0, at(17, 20), at(18, 0)},
+		{ChangeFrom, 0, at(18, 2), at(18, 18)},
+		{ChangeBecoming, 0, at(18, 8), at(18, 10)},
+		{ChangeProb, 30, at(18, 12), at(18, 16)},
+		{ChangeFrom, 0, at(18, 18), at(19, 0)},
+	}
+	if len(taf.Segments) != len(want) {
+		t.Fatalf("got %d segments, want %d: %+v", len(taf.Segments), len(want), taf.Segments)
+	}
+	for i, w := range want {
+		s := taf.Segments[i]
+		if s.Change != w.change || s.Probability != w.prob || !s.From.Equal(w.from) || !s.To.Equal(w.to) {
+			t.Errorf("segment %d = %q %d %v–%v, want %q %d %v–%v", i, s.Change, s.Probability, s.From, s.To, w.change, w.prob, w.from, w.to)
+		}
+	}
+
+	initial := taf.Segments[0]
+	if initial.Wind == nil || !near(initial.Wind.Direction, 270) || !near(initial.Wind.Gust, 46.3) || !initial.Visibility.MoreThan {
+		t.Errorf("initial wind/visibility = %+v %+v", initial.Wind, initial.Visibility)
+	}
+	if initial.SkyCondition() != "SCT080 BKN200" || !near(initial.Ceiling(), 6096) {
+		t.Errorf("initial sky = %q ceiling %v", initial.SkyCondition(), initial.Ceiling())
+	}
+
+	tempo := taf.Segments[1]
+	if tempo.Raw != "TEMPO 1720/1724 VRB20G35KT 3SM TSRA BKN060CB" || tempo.WeatherCodes() != "TSRA" || tempo.Wind.Direction != nil {
+		t.Errorf("tempo = %+v", tempo)
+	}
+	if prob := taf.Segments[4]; prob.WeatherCodes() != "SN" || !near(prob.Ceiling(), 243.84) {
+		t.Errorf("prob30 = %+v", prob)
+	}
+	if last := taf.Segments[5]; last.WeatherCodes() != "NSW" || last.Ceiling() != nil {
+		t.Errorf("last = %+v", last)
+	}
+}
+
+func TestParseTAF_Variants(t *testing.T) {
+	issued := time.Date(2024, 3, 31, 23, 40, 0, 0, time.UTC)
+	taf, err := ParseTAF("TAF AMD KBJC 312340Z 0100/0124 VRB03KT P6SM SKC PROB40 TEMPO 0120/0124 2SM -TSRA RMK NXT FCST BY 06Z", issued)
+	if err != nil {
+		t.Fatalf("ParseTAF: %v", err)
+	}
+	if !taf.Amended || taf.Station != "KBJC" {
+		t.Errorf("header = %+v", taf)
+	}
+	if !taf.ValidFrom.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)) || !taf.ValidTo.Equal(time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("valid = %v to %v", taf.ValidFrom, taf.ValidTo)
+	}
+	if len(taf.Segments) != 2 {
+		t.Fatalf("segments = %+v", taf.Segments)
+	}
+	if s := taf.Segments[1]; s.Change != ChangeProbTempo || s.Probability != 40 || s.WeatherCodes() != "-TSRA" {
+		t.Errorf("prob tempo = %+v", s)
+	}
+
+	// Groups without a period span the segment they fall in.
+	taf, err = ParseTAF("TAF KDEN 171720Z 1718/1824 27015KT P6SM SCT080 TEMPO BKN050 FM180200 31010KT P6SM SKC BECMG 33005KT", issued)
+	if err != nil {
+		t.Fatalf("ParseTAF: %v", err)
+	}
+	wants := []struct {
+		change   string
+		from, to time.Time
+	}{
+		{ChangeInitial, time.Date(2024, 3, 17, 18, 0, 0, 0, time.UTC), time.Date(2024, 3, 18, 2, 0, 0, 0, time.UTC)},
+		{ChangeTemporary, time.Date(2024, 3, 17, 18, 0, 0, 0, time.UTC), time.Date(2024, 3, 18, 2, 0, 0, 0, time.UTC)},
+		{ChangeFrom, time.Date(2024, 3, 18, 2, 0, 0, 0, time.UTC), time.Date(2024, 3, 19, 0, 0, 0, 0, time.UTC)},
+		{ChangeBecoming, time.Date(2024, 3, 18, 2, 0, 0, 0, time.UTC), time.Date(2024, 3, 19, 0, 0, 0, 0, time.UTC)},
+	}
+	if len(taf.Segments) != len(wants) {
+		t.Fatalf("segments = %+v", taf.Segments)
+	}
+	for i, want := range wants {
+		if s := taf.Segments[i]; s.Change != want.change || !s.From.Equal(want.from) || !s.To.Equal(want.to) {
+			t.Errorf("segment %d = %q %v to %v, want %q %v to %v", i, s.Change, s.From, s.To, want.change, want.from, want.to)
+		}
+	}
+
+	for _, text := range []string{"", "TAF", "TAF KDEN 171720Z", "TAF KDEN 171720Z 27015KT"} {
+		if _, err := ParseTAF(text, issued); err == nil {
+			t.Errorf("ParseTAF(%q): expected an error", text)
+		}
+	}
+}
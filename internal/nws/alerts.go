@@ -0,0 +1,105 @@
+package nws
+
+import (
+	"sort"
+	"strings"
+)
+
+// AlertsResponse is the GeoJSON collection returned by /alerts/active
+// (with ?point= or /zone/{id}).
+type AlertsResponse struct {
+	Title    string `json:"title"`
+	Features []struct {
+		Properties Alert `json:"properties"`
+	} `json:"features"`
+}
+
+// Alerts returns the alert properties of every feature in the response,
+// ordered most severe first.
+func (r AlertsResponse) Alerts() []Alert {
+	alerts := make([]Alert, 0, len(r.Features))
+	for _, f := range r.Features {
+		alerts = append(alerts, f.Properties)
+	}
+	SortAlerts(alerts)
+	return alerts
+}
+
+// Alert is a single watch, warning, advisory or statement.
+type Alert struct {
+	ID            string   `json:"id"`
+	AreaDesc      string   `json:"areaDesc"`
+	AffectedZones []string `json:"affectedZones"`
+	Sent          string   `json:"sent"`
+	Effective     string   `json:"effective"`
+	Onset         string   `json:"onset"`
+	Expires       string   `json:"expires"`
+	Ends          string   `json:"ends"`
+	Status        string   `json:"status"`
+	MessageType   string   `json:"messageType"`
+	Category      string   `json:"category"`
+	Severity      string   `json:"severity"`
+	Certainty     string   `json:"certainty"`
+	Urgency       string   `json:"urgency"`
+	Event         string   `json:"event"`
+	SenderName    string   `json:"senderName"`
+	Headline      string   `json:"headline"`
+	Description   string   `json:"description"`
+	Instruction   string   `json:"instruction"`
+	Response      string   `json:"response"`
+}
+
+// Until returns the timestamp the alert is in effect until, preferring
+// the event end time over the message expiry.
+func (a Alert) Until() string {
+	if a.Ends != "" {
+		return a.Ends
+	}
+	return a.Expires
+}
+
+// ZoneIDs returns the bare zone identifiers (e.g. COZ039) of the
+// affected zone URLs.
+func (a Alert) ZoneIDs() []string {
+	ids := make([]string, 0, len(a.AffectedZones))
+	for _, z := range a.AffectedZones {
+		ids = append(ids, z[strings.LastIndex(z, "/")+1:])
+	}
+	return ids
+}
+
+var severityRank = map[string]int{
+	"Extreme":  4,
+	"Severe":   3,
+	"Moderate": 2,
+	"Minor":    1,
+}
+
+// SeverityRank orders CAP severities from Extreme (4) down to Unknown (0).
+func SeverityRank(severity string) int {
+	return severityRank[severity]
+}
+
+// SortAlerts orders alerts most severe first, then by onset time.
+func SortAlerts(alerts []Alert) {
+	sort.SliceStable(alerts, func(i, j int) bool {
+		ri, rj := SeverityRank(alerts[i].Severity), SeverityRank(alerts[j].Severity)
+		if ri != rj {
+			return ri > rj
+		}
+		return alerts[i].Onset < alerts[j].Onset
+	})
+}
+
+// AlertSummary returns a one-line description such as
+// "High Wind Warning (Severe) until Feb 17 18:00".
+func AlertSummary(a Alert) string {
+	s := a.Event
+	if a.Severity != "" && a.Severity != "Unknown" {
+		s += " (" + a.Severity + ")"
+	}
+	if until := a.Until(); until != "" {
+		s += " until " + FormatTime(until)
+	}
+	return s
+}
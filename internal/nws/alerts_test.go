@@ -0,0 +1,118 @@
+package nws
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const alertsJSON = `{
+	"title": "Current watches, warnings, and advisories for 39.7392 N, 104.9903 W",
+	"features": [
+		{"properties": {
+			"id": "urn:oid:2.49.0.1.840.0.1",
+			"areaDesc": "Denver",
+			"affectedZones": ["https://api.weather.gov/zones/forecast/COZ039", "https://api.weather.gov/zones/county/COC031"],
+			"onset": "2026-02-17T10:00:00-07:00",
+			"expires": "2026-02-17T12:00:00-07:00",
+			"ends": null,
+			"severity": "Minor",
+			"certainty": "Likely",
+			"urgency": "Expected",
+			"event": "Wind Advisory",
+			"headline": "Wind Advisory issued February 17",
+			"description": "Gusts up to 50 mph.",
+			"instruction": null
+		}},
+		{"properties": {
+			"id": "urn:oid:2.49.0.1.840.0.2",
+			"onset": "2026-02-17T11:00:00-07:00",
+			"ends": "2026-02-17T18:00:00-07:00",
+			"severity": "Severe",
+			"event": "High Wind Warning",
+			"instruction": "Secure outdoor objects."
+		}}
+	]
+}`
+
+func TestAlertsResponse_UnmarshalJSON(t *testing.T) {
+	var resp AlertsResponse
+	if err := json.Unmarshal([]byte(alertsJSON), &resp); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if len(resp.Features) != 2 {
+		t.Fatalf("len(Features) = %d, want 2", len(resp.Features))
+	}
+	a := resp.Features[0].Properties
+	if a.Event != "Wind Advisory" || a.Certainty != "Likely" || a.Urgency != "Expected" {
+		t.Errorf("unexpected alert %+v", a)
+	}
+	if a.Ends != "" || a.Instruction != "" {
+		t.Errorf("null fields should decode as empty, got ends=%q instruction=%q", a.Ends, a.Instruction)
+	}
+	if len(a.AffectedZones) != 2 {
+		t.Errorf("len(AffectedZones) = %d, want 2", len(a.AffectedZones))
+	}
+}
+
+func TestAlertsResponse_Alerts(t *testing.T) {
+	var resp AlertsResponse
+	if err := json.Unmarshal([]byte(alertsJSON), &resp); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	alerts := resp.Alerts()
+	if len(alerts) != 2 {
+		t.Fatalf("len(Alerts()) = %d, want 2", len(alerts))
+	}
+	if alerts[0].Event != "High Wind Warning" {
+		t.Errorf("Alerts()[0] = %q, want most severe first", alerts[0].Event)
+	}
+}
+
+func TestAlert_Until(t *testing.T) {
+	a := Alert{Expires: "expires", Ends: "ends"}
+	if got := a.Until(); got != "ends" {
+		t.Errorf("Until() = %q, want ends", got)
+	}
+	a.Ends = ""
+	if got := a.Until(); got != "expires" {
+		t.Errorf("Until() = %q, want expires", got)
+	}
+}
+
+func TestAlert_ZoneIDs(t *testing.T) {
+	a := Alert{AffectedZones: []string{
+		"https://api.weather.gov/zones/forecast/COZ039",
+		"COZ040",
+	}}
+	got := a.ZoneIDs()
+	if len(got) != 2 || got[0] != "COZ039" || got[1] != "COZ040" {
+		t.Errorf("ZoneIDs() = %v, want [COZ039 COZ040]", got)
+	}
+}
+
+func TestSortAlerts(t *testing.T) {
+	alerts := []Alert{
+		{Event: "b", Severity: "Minor", Onset: "2026-02-17T12:00:00Z"},
+		{Event: "c", Severity: "Unknown"},
+		{Event: "a", Severity: "Minor", Onset: "2026-02-17T10:00:00Z"},
+		{Event: "d", Severity: "Extreme"},
+	}
+	SortAlerts(alerts)
+	want := []string{"d", "a", "b", "c"}
+	for i, w := range want {
+		if alerts[i].Event != w {
+			t.Errorf("alerts[%d] = %q, want %q", i, alerts[i].Event, w)
+		}
+	}
+}
+
+func TestAlertSummary(t *testing.T) {
+	a := Alert{Event: "Wind Advisory", Severity: "Minor", Expires: "not-a-timestamp"}
+	if got := AlertSummary(a); got != "Wind Advisory (Minor) until not-a-timestamp" {
+		t.Errorf("AlertSummary() = %q", got)
+	}
+	a = Alert{Event: "Special Weather Statement", Severity: "Unknown"}
+	if got := AlertSummary(a); got != "Special Weather Statement" {
+		t.Errorf("AlertSummary() = %q", got)
+	}
+}
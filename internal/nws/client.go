@@ -1,38 +1,294 @@
 package nws
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const userAgent = "(lastwind, github.com/nehpe/lastwind)"
 
-var Client = &http.Client{}
+// DefaultBaseURL is the root of the NWS API.
+const DefaultBaseURL = "https://api.weather.gov"
 
-func FetchJSON[T any](url string) (T, error) {
-	var result T
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return result, err
+// RetryPolicy controls how requests that fail with 429 or 5xx responses
+// are retried. Delays double after each attempt, starting at BaseDelay and
+// capped at MaxDelay; a Retry-After header takes precedence when present.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy retries up to three times over roughly 3.5 seconds.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// Client talks to the NWS API.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	UserAgent  string
+	Retry      RetryPolicy
+}
+
+// NewClient returns a client for the public NWS API with a 30 second
+// request timeout and the default retry policy.
+func NewClient() *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		UserAgent:  userAgent,
+		Retry:      DefaultRetryPolicy,
 	}
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Accept", "application/geo+json")
+}
 
-	resp, err := Client.Do(req)
+// Point returns the metadata for a lat/lon, including its forecast links.
+func (c *Client) Point(ctx context.Context, lat, lon float64) (PointsResponse, error) {
+	var resp PointsResponse
+	err := c.Get(ctx, fmt.Sprintf("/points/%.4f,%.4f", lat, lon), &resp)
+	return resp, err
+}
+
+// Station returns the metadata for a station identifier.
+func (c *Client) Station(ctx context.Context, id string) (StationResponse, error) {
+	var resp StationResponse
+	err := c.Get(ctx, "/stations/"+url.PathEscape(id), &resp)
+	return resp, err
+}
+
+// Stations fetches a station collection, such as a point's
+// observationStations link.
+func (c *Client) Stations(ctx context.Context, stationsURL string) (StationsResponse, error) {
+	var resp StationsResponse
+	err := c.Get(ctx, stationsURL, &resp)
+	return resp, err
+}
+
+// NearbyStations returns the observation stations listed for a point,
+// nearest first.
+func (c *Client) NearbyStations(ctx context.Context, lat, lon float64) (StationsResponse, error) {
+	points, err := c.Point(ctx, lat, lon)
 	if err != nil {
-		return result, err
+		return StationsResponse{}, err
+	}
+	if points.Properties.ObservationStations == "" {
+		return StationsResponse{}, fmt.Errorf("no observation stations listed for %.4f, %.4f", lat, lon)
+	}
+	return c.Stations(ctx, points.Properties.ObservationStations)
+}
+
+// StationOptions filters the station list. States are two-letter state
+// or territory codes; Limit is the page size.
+type StationOptions struct {
+	States []string
+	Limit  int
+}
+
+// AllStations returns every station matching opts, following
+// pagination.next links until a page comes back empty.
+func (c *Client) AllStations(ctx context.Context, opts StationOptions) ([]StationResponse, error) {
+	q := url.Values{}
+	if len(opts.States) > 0 {
+		q.Set("state", strings.ToUpper(strings.Join(opts.States, ",")))
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
 	}
-	defer resp.Body.Close()
+	path := "/stations"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var resp StationsResponse
+	err := c.Get(ctx, path, &resp)
+	var all []StationResponse
+	seen := make(map[string]bool)
+	for err == nil {
+		all = append(all, resp.Features...)
+		next := resp.Pagination.Next
+		if len(resp.Features) == 0 || next == "" || seen[next] {
+			return all, nil
+		}
+		seen[next] = true
+		resp = StationsResponse{}
+		err = c.Get(ctx, next, &resp)
+	}
+	return all, err
+}
+
+// ObservationOptions filters a station's observations. Limit is the page
+// size; zero Start and End times leave the range open.
+type ObservationOptions struct {
+	Limit      int
+	Start, End time.Time
+}
+
+// Observations returns one page of a station's observations, newest
+// first. Use AllObservations to follow the pagination links.
+func (c *Client) Observations(ctx context.Context, id string, opts ObservationOptions) (ObservationsResponse, error) {
+	q := url.Values{}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if !opts.Start.IsZero() {
+		q.Set("start", opts.Start.UTC().Format(time.RFC3339))
+	}
+	if !opts.End.IsZero() {
+		q.Set("end", opts.End.UTC().Format(time.RFC3339))
+	}
+	path := "/stations/" + url.PathEscape(id) + "/observations"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var resp ObservationsResponse
+	err := c.Get(ctx, path, &resp)
+	return resp, err
+}
+
+// AllObservations returns every observation matching opts, newest first,
+// following pagination.next links until a page comes back empty.
+func (c *Client) AllObservations(ctx context.Context, id string, opts ObservationOptions) ([]Observation, error) {
+	resp, err := c.Observations(ctx, id, opts)
+	var all []Observation
+	seen := make(map[string]bool)
+	for err == nil {
+		for _, f := range resp.Features {
+			all = append(all, f.Properties)
+		}
+		next := resp.Pagination.Next
+		if len(resp.Features) == 0 || next == "" || seen[next] {
+			return all, nil
+		}
+		seen[next] = true
+		resp = ObservationsResponse{}
+		err = c.Get(ctx, next, &resp)
+	}
+	return all, err
+}
+
+// LatestObservation returns a station's most recent observation.
+func (c *Client) LatestObservation(ctx context.Context, id string) (ObservationResponse, error) {
+	var resp ObservationResponse
+	err := c.Get(ctx, "/stations/"+url.PathEscape(id)+"/observations/latest", &resp)
+	return resp, err
+}
+
+// Forecast fetches a forecast link from a point (forecast or
+// forecastHourly).
+func (c *Client) Forecast(ctx context.Context, forecastURL string) (ForecastResponse, error) {
+	var resp ForecastResponse
+	err := c.Get(ctx, forecastURL, &resp)
+	return resp, err
+}
+
+// Gridpoint fetches a point's forecastGridData link.
+func (c *Client) Gridpoint(ctx context.Context, gridURL string) (GridpointResponse, error) {
+	var resp GridpointResponse
+	err := c.Get(ctx, gridURL, &resp)
+	return resp, err
+}
+
+// PointAlerts returns the alerts active at a lat/lon.
+func (c *Client) PointAlerts(ctx context.Context, lat, lon float64) (AlertsResponse, error) {
+	var resp AlertsResponse
+	err := c.Get(ctx, fmt.Sprintf("/alerts/active?point=%.4f,%.4f", lat, lon), &resp)
+	return resp, err
+}
+
+// ZoneAlerts returns the alerts active in a forecast or county zone.
+func (c *Client) ZoneAlerts(ctx context.Context, zone string) (AlertsResponse, error) {
+	var resp AlertsResponse
+	err := c.Get(ctx, "/alerts/active/zone/"+url.PathEscape(zone), &resp)
+	return resp, err
+}
+
+// Get fetches a URL and decodes the JSON response into v. Paths starting
+// with "/" are resolved against BaseURL; absolute URLs (such as links
+// returned by the API) are used as-is.
+func (c *Client) Get(ctx context.Context, target string, v any) error {
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimSuffix(c.BaseURL, "/") + target
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, target)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			err := json.NewDecoder(resp.Body).Decode(v)
+			resp.Body.Close()
+			return err
+		}
 
-	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return result, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		resp.Body.Close()
+		if !retryable(resp.StatusCode) || attempt >= c.Retry.MaxRetries {
+			return newAPIError(resp.StatusCode, body)
+		}
+
+		timer := time.NewTimer(c.retryDelay(attempt, resp.Header.Get("Retry-After")))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, target string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	ua := c.UserAgent
+	if ua == "" {
+		ua = userAgent
+	}
+	req.Header.Set("User-Agent", ua)
+	req.Header.Set("Accept", "application/geo+json")
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return hc.Do(req)
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryDelay returns how long to wait before retrying after the given
+// attempt, honouring a Retry-After header in seconds or HTTP-date form.
+func (c *Client) retryDelay(attempt int, retryAfter string) time.Duration {
+	var delay time.Duration
+	if secs, err := strconv.Atoi(retryAfter); err == nil {
+		delay = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(retryAfter); err == nil {
+		delay = time.Until(t)
+	} else {
+		delay = c.Retry.BaseDelay << attempt
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return result, err
+	if delay < 0 {
+		delay = 0
+	}
+	if c.Retry.MaxDelay > 0 && delay > c.Retry.MaxDelay {
+		delay = c.Retry.MaxDelay
 	}
-	return result, nil
+	return delay
 }
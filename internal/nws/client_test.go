@@ -1,13 +1,25 @@
 package nws
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
-func TestFetchJSON_Success(t *testing.T) {
+func testClient(url string) *Client {
+	c := NewClient()
+	c.BaseURL = url
+	c.Retry = RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	return c
+}
+
+func TestClientGet_Success(t *testing.T) {
 	type testPayload struct {
 		Name string `json:"name"`
 		Age  int    `json:"age"`
@@ -25,46 +37,330 @@ func TestFetchJSON_Success(t *testing.T) {
 	}))
 	defer server.Close()
 
-	result, err := FetchJSON[testPayload](server.URL)
-	if err != nil {
-		t.Fatalf("FetchJSON() error = %v", err)
+	var result testPayload
+	if err := testClient(server.URL).Get(context.Background(), server.URL, &result); err != nil {
+		t.Fatalf("Get() error = %v", err)
 	}
 	if result.Name != "test" || result.Age != 42 {
-		t.Errorf("FetchJSON() = %+v, want {Name:test Age:42}", result)
+		t.Errorf("Get() = %+v, want {Name:test Age:42}", result)
+	}
+}
+
+func TestClientGet_RelativePath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stations/KDEN" {
+			t.Errorf("path = %q, want /stations/KDEN", r.URL.Path)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var v struct{}
+	if err := testClient(server.URL+"/").Get(context.Background(), "/stations/KDEN", &v); err != nil {
+		t.Fatalf("Get() error = %v", err)
 	}
 }
 
-func TestFetchJSON_HTTPError(t *testing.T) {
+func TestClientGet_HTTPError(t *testing.T) {
+	var calls atomic.Int32
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
 		w.WriteHeader(404)
 		w.Write([]byte("not found"))
 	}))
 	defer server.Close()
 
-	type empty struct{}
-	_, err := FetchJSON[empty](server.URL)
+	var v struct{}
+	err := testClient(server.URL).Get(context.Background(), "/", &v)
 	if err == nil {
-		t.Fatal("FetchJSON() expected error for 404, got nil")
+		t.Fatal("Get() expected error for 404, got nil")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("404 requested %d times, want no retries", calls.Load())
 	}
 }
 
-func TestFetchJSON_InvalidJSON(t *testing.T) {
+func TestClientGet_InvalidJSON(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("not json"))
 	}))
 	defer server.Close()
 
-	type empty struct{}
-	_, err := FetchJSON[empty](server.URL)
-	if err == nil {
-		t.Fatal("FetchJSON() expected error for invalid JSON, got nil")
+	var v struct{}
+	if err := testClient(server.URL).Get(context.Background(), "/", &v); err == nil {
+		t.Fatal("Get() expected error for invalid JSON, got nil")
+	}
+}
+
+func TestClientGet_ConnectionError(t *testing.T) {
+	var v struct{}
+	if err := testClient("http://localhost:1").Get(context.Background(), "/", &v); err == nil {
+		t.Fatal("Get() expected connection error, got nil")
+	}
+}
+
+func TestClientGet_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer server.Close()
+
+	var v struct{ OK bool }
+	if err := testClient(server.URL).Get(context.Background(), "/", &v); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !v.OK || calls.Load() != 3 {
+		t.Errorf("Get() = %+v after %d calls, want ok after 3", v, calls.Load())
+	}
+}
+
+func TestClientGet_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	var v struct{}
+	if err := testClient(server.URL).Get(context.Background(), "/", &v); err == nil {
+		t.Fatal("Get() expected error, got nil")
+	}
+	if calls.Load() != 3 {
+		t.Errorf("requested %d times, want 3 (1 + 2 retries)", calls.Load())
+	}
+}
+
+func TestClientGet_ContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := testClient(server.URL)
+	c.Retry.MaxDelay = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var v struct{}
+	start := time.Now()
+	if err := c.Get(ctx, "/", &v); err != context.DeadlineExceeded {
+		t.Errorf("Get() error = %v, want context.DeadlineExceeded", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Get() did not stop waiting when the context was cancelled")
+	}
+}
+
+func TestClient_RetryDelay(t *testing.T) {
+	c := &Client{Retry: RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}}
+	tests := []struct {
+		attempt    int
+		retryAfter string
+		want       time.Duration
+	}{
+		{0, "", time.Second},
+		{2, "", 4 * time.Second},
+		{5, "", 10 * time.Second},
+		{0, "3", 3 * time.Second},
+		{0, "120", 10 * time.Second},
+		{0, "garbage", time.Second},
+		{0, time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		if got := c.retryDelay(tt.attempt, tt.retryAfter); got != tt.want {
+			t.Errorf("retryDelay(%d, %q) = %v, want %v", tt.attempt, tt.retryAfter, got, tt.want)
+		}
+	}
+}
+
+func TestClient_Endpoints(t *testing.T) {
+	var gotURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.RequestURI()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server.URL)
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"Point", func() error { _, err := c.Point(ctx, 39.73921, -104.99034); return err }, "/points/39.7392,-104.9903"},
+		{"Station", func() error { _, err := c.Station(ctx, "KDEN"); return err }, "/stations/KDEN"},
+		{"Observations", func() error {
+			_, err := c.Observations(ctx, "KDEN", ObservationOptions{Limit: 500})
+			return err
+		}, "/stations/KDEN/observations?limit=500"},
+		{"Observations range", func() error {
+			_, err := c.Observations(ctx, "KDEN", ObservationOptions{
+				Start: time.Date(2026, 2, 17, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 2, 18, 12, 0, 0, 0, time.FixedZone("MST", -7*3600)),
+			})
+			return err
+		}, "/stations/KDEN/observations?end=2026-02-18T19%3A00%3A00Z&start=2026-02-17T00%3A00%3A00Z"},
+		{"LatestObservation", func() error { _, err := c.LatestObservation(ctx, "KDEN"); return err }, "/stations/KDEN/observations/latest"},
+		{"Stations", func() error { _, err := c.Stations(ctx, server.URL+"/gridpoints/BOU/62,60/stations"); return err }, "/gridpoints/BOU/62,60/stations"},
+		{"AllStations", func() error {
+			_, err := c.AllStations(ctx, StationOptions{States: []string{"co", "WY"}, Limit: 500})
+			return err
+		}, "/stations?limit=500&state=CO%2CWY"},
+		{"Forecast", func() error { _, err := c.Forecast(ctx, server.URL+"/gridpoints/BOU/62,60/forecast"); return err }, "/gridpoints/BOU/62,60/forecast"},
+		{"Gridpoint", func() error { _, err := c.Gridpoint(ctx, server.URL+"/gridpoints/BOU/62,60"); return err }, "/gridpoints/BOU/62,60"},
+		{"PointAlerts", func() error { _, err := c.PointAlerts(ctx, 39.7392, -104.9903); return err }, "/alerts/active?point=39.7392,-104.9903"},
+		{"ZoneAlerts", func() error { _, err := c.ZoneAlerts(ctx, "COZ039"); return err }, "/alerts/active/zone/COZ039"},
+		{"Products", func() error { _, err := c.Products(ctx, "TAF", "KDEN"); return err }, "/products/types/TAF/locations/KDEN"},
+		{"Product", func() error { _, err := c.Product(ctx, "a1b2-c3"); return err }, "/products/a1b2-c3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if gotURL != tt.want {
+				t.Errorf("requested %q, want %q", gotURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_AllObservations(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := func(next string, timestamps ...string) {
+			var features []string
+			for _, ts := range timestamps {
+				features = append(features, `{"properties":{"timestamp":"`+ts+`"}}`)
+			}
+			w.Write([]byte(`{"features":[` + strings.Join(features, ",") + `],"pagination":{"next":"` + next + `"}}`))
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			if r.URL.Query().Get("start") != "2026-02-17T00:00:00Z" {
+				t.Errorf("start = %q", r.URL.Query().Get("start"))
+			}
+			page(server.URL+"/stations/KDEN/observations?cursor=2", "2026-02-17T12:00:00Z", "2026-02-17T11:00:00Z")
+		case "2":
+			page(server.URL+"/stations/KDEN/observations?cursor=3", "2026-02-17T10:00:00Z")
+		case "3":
+			page(server.URL + "/stations/KDEN/observations?cursor=4")
+		default:
+			t.Errorf("requested %s after an empty page", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	obs, err := testClient(server.URL).AllObservations(context.Background(), "KDEN",
+		ObservationOptions{Start: time.Date(2026, 2, 17, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("AllObservations() error = %v", err)
+	}
+	if len(obs) != 3 || obs[2].Timestamp != "2026-02-17T10:00:00Z" {
+		t.Errorf("AllObservations() = %d observations, want 3 across pages", len(obs))
 	}
 }
 
-func TestFetchJSON_ConnectionError(t *testing.T) {
-	type empty struct{}
-	_, err := FetchJSON[empty]("http://localhost:1")
+func TestClient_AllObservations_Error(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") != "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"features":[{"properties":{"timestamp":"2026-02-17T12:00:00Z"}}],"pagination":{"next":"` + server.URL + `/?cursor=2"}}`))
+	}))
+	defer server.Close()
+
+	obs, err := testClient(server.URL).AllObservations(context.Background(), "KDEN", ObservationOptions{})
 	if err == nil {
-		t.Fatal("FetchJSON() expected connection error, got nil")
+		t.Fatal("AllObservations() expected error for a failed page")
+	}
+	if len(obs) != 1 {
+		t.Errorf("AllObservations() returned %d observations before the error, want 1", len(obs))
+	}
+}
+
+func TestClient_AllStations(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"features":[{"properties":{"stationIdentifier":"KDEN"}},{"properties":{"stationIdentifier":"KBJC"}}],
+				"pagination":{"next":"` + server.URL + `/stations?state=CO&cursor=2"}}`))
+		case "2":
+			w.Write([]byte(`{"features":[{"properties":{"stationIdentifier":"KAPA"}}],
+				"pagination":{"next":"` + server.URL + `/stations?state=CO&cursor=3"}}`))
+		default:
+			w.Write([]byte(`{"features":[],"pagination":{"next":"` + server.URL + `/stations?state=CO&cursor=4"}}`))
+		}
+	}))
+	defer server.Close()
+
+	stations, err := testClient(server.URL).AllStations(context.Background(), StationOptions{States: []string{"CO"}})
+	if err != nil {
+		t.Fatalf("AllStations() error = %v", err)
+	}
+	if len(stations) != 3 || stations[2].Properties.StationIdentifier != "KAPA" {
+		t.Errorf("AllStations() = %d stations, want 3 across pages", len(stations))
+	}
+}
+
+func TestClient_NearbyStations(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/points/39.7392,-104.9903":
+			w.Write([]byte(`{"properties":{"observationStations":"` + server.URL + `/gridpoints/BOU/62,60/stations"}}`))
+		case "/gridpoints/BOU/62,60/stations":
+			w.Write([]byte(`{"features":[{"properties":{"stationIdentifier":"KBJC"}}]}`))
+		default:
+			w.Write([]byte(`{"properties":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	c := testClient(server.URL)
+	resp, err := c.NearbyStations(context.Background(), 39.7392, -104.9903)
+	if err != nil || len(resp.Features) != 1 {
+		t.Fatalf("NearbyStations() = %+v, %v", resp, err)
+	}
+	if _, err := c.NearbyStations(context.Background(), 0, 0); err == nil {
+		t.Error("NearbyStations() expected error when the point lists no stations")
+	}
+}
+
+func TestClient_LatestTAF(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/products/types/TAF/locations/DEN":
+			w.Write([]byte(`{"@graph":[{"id":"new"},{"id":"old"}]}`))
+		case "/products/new":
+			w.Write([]byte(`{"id":"new","productCode":"TAF","productText":"TAF KDEN 171720Z 1718/1824 27015KT P6SM SKC"}`))
+		default:
+			w.Write([]byte(`{"@graph":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	c := testClient(server.URL)
+	p, err := c.LatestTAF(context.Background(), "kden")
+	if err != nil || p.ID != "new" || !strings.HasPrefix(p.ProductText, "TAF KDEN") {
+		t.Fatalf("LatestTAF() = %+v, %v", p, err)
+	}
+	if _, err := c.LatestTAF(context.Background(), "KBJC"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("LatestTAF() error = %v, want ErrNotFound", err)
 	}
 }
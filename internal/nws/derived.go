@@ -0,0 +1,132 @@
+package nws
+
+import "math"
+
+// Derived holds quantities computed from an observation. Each is nil when
+// its inputs are missing or it doesn't apply to the conditions. Heat
+// index, wind chill, feels-like and wet-bulb temperatures are in °C,
+// vapor pressure in hPa and absolute humidity in g/m³.
+type Derived struct {
+	HeatIndex        *float64
+	WindChill        *float64
+	FeelsLike        *float64
+	WetBulb          *float64
+	VaporPressure    *float64
+	AbsoluteHumidity *float64
+}
+
+// Thresholds from the NWS: heat index applies at 80°F and above, wind
+// chill at 50°F and below with wind of at least 3 mph.
+const (
+	heatIndexMinF   = 80.0
+	windChillMaxF   = 50.0
+	windChillMinMph = 3.0
+)
+
+// Derive computes the derived quantities for an observation. Relative
+// humidity is calculated from the dewpoint when the station doesn't
+// report it.
+func Derive(o Observation) Derived {
+	var d Derived
+	t := o.Temperature.Value
+	rh := o.RelativeHumidity.Value
+	if rh == nil && t != nil && o.Dewpoint.Value != nil {
+		v := RelativeHumidity(*t, *o.Dewpoint.Value)
+		rh = &v
+	}
+
+	if t != nil && rh != nil && CToF(*t) >= heatIndexMinF {
+		v := HeatIndex(*t, *rh)
+		d.HeatIndex = &v
+	}
+	if t != nil && o.WindSpeed.Value != nil {
+		if v, ok := WindChill(*t, *o.WindSpeed.Value); ok {
+			d.WindChill = &v
+		}
+	}
+	if t != nil {
+		v := *t
+		switch {
+		case d.HeatIndex != nil:
+			v = *d.HeatIndex
+		case d.WindChill != nil:
+			v = *d.WindChill
+		}
+		d.FeelsLike = &v
+	}
+	if t != nil && rh != nil {
+		v := WetBulb(*t, *rh)
+		d.WetBulb = &v
+	}
+	if o.Dewpoint.Value != nil {
+		e := VaporPressure(*o.Dewpoint.Value)
+		d.VaporPressure = &e
+		if t != nil {
+			v := AbsoluteHumidity(*t, e)
+			d.AbsoluteHumidity = &v
+		}
+	}
+	return d
+}
+
+// HeatIndex returns the NWS heat index in °C for a temperature in °C and
+// relative humidity in percent, using the Rothfusz regression with the
+// NWS low- and high-humidity adjustments.
+func HeatIndex(tempC, rh float64) float64 {
+	t := CToF(tempC)
+	hi := 0.5 * (t + 61.0 + (t-68.0)*1.2 + rh*0.094)
+	if (hi+t)/2 >= 80 {
+		hi = -42.379 + 2.04901523*t + 10.14333127*rh -
+			0.22475541*t*rh - 0.00683783*t*t - 0.05481717*rh*rh +
+			0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+		if rh < 13 && t >= 80 && t <= 112 {
+			hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		} else if rh > 85 && t >= 80 && t <= 87 {
+			hi += (rh - 85) / 10 * (87 - t) / 5
+		}
+	}
+	return fToC(hi)
+}
+
+// WindChill returns the NWS wind chill in °C for a temperature in °C and
+// wind speed in km/h. It reports false outside the formula's range of
+// 50°F and below with wind of at least 3 mph.
+func WindChill(tempC, windKmh float64) (float64, bool) {
+	t := CToF(tempC)
+	v := KmhToMph(windKmh)
+	if t > windChillMaxF || v < windChillMinMph {
+		return 0, false
+	}
+	p := math.Pow(v, 0.16)
+	return fToC(35.74 + 0.6215*t - 35.75*p + 0.4275*t*p), true
+}
+
+// WetBulb returns the wet-bulb temperature in °C for a temperature in °C
+// and relative humidity in percent, using Stull's (2011) approximation.
+func WetBulb(tempC, rh float64) float64 {
+	return tempC*math.Atan(0.151977*math.Sqrt(rh+8.313659)) +
+		math.Atan(tempC+rh) - math.Atan(rh-1.676331) +
+		0.00391838*math.Pow(rh, 1.5)*math.Atan(0.023101*rh) - 4.686035
+}
+
+// VaporPressure returns the actual vapor pressure in hPa for a dewpoint
+// in °C (Bolton, 1980).
+func VaporPressure(dewpointC float64) float64 {
+	return 6.112 * math.Exp(17.67*dewpointC/(dewpointC+243.5))
+}
+
+// AbsoluteHumidity returns the mass of water vapor per cubic metre of air
+// in g/m³ for a temperature in °C and vapor pressure in hPa.
+func AbsoluteHumidity(tempC, vaporPressure float64) float64 {
+	return 216.7 * vaporPressure / (tempC + 273.15)
+}
+
+// RelativeHumidity returns the relative humidity in percent for a
+// temperature and dewpoint in °C.
+func RelativeHumidity(tempC, dewpointC float64) float64 {
+	return 100 * VaporPressure(dewpointC) / VaporPressure(tempC)
+}
+
+func fToC(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
@@ -0,0 +1,109 @@
+package nws
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHeatIndex(t *testing.T) {
+	tests := []struct {
+		tempF, rh, wantF float64
+	}{
+		{90, 50, 94.6},
+		{100, 40, 109.3},
+		{80, 40, 79.6},
+		{96, 10, 90.4},  // low humidity adjustment
+		{85, 90, 101.8}, // high humidity adjustment
+		{70, 50, 69.0},  // simple formula
+	}
+	for _, tt := range tests {
+		got := CToF(HeatIndex(fToC(tt.tempF), tt.rh))
+		if math.Abs(got-tt.wantF) > 0.2 {
+			t.Errorf("HeatIndex(%v°F, %v%%) = %.1f°F, want %.1f°F", tt.tempF, tt.rh, got, tt.wantF)
+		}
+	}
+}
+
+func TestWindChill(t *testing.T) {
+	tests := []struct {
+		tempF, mph, wantF float64
+		ok                bool
+	}{
+		{0, 15, -19.4, true},
+		{30, 10, 21.2, true},
+		{50, 5, 48.2, true},
+		{51, 10, 0, false},
+		{20, 2, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := WindChill(fToC(tt.tempF), tt.mph*1.609344)
+		if ok != tt.ok || (ok && math.Abs(CToF(got)-tt.wantF) > 0.2) {
+			t.Errorf("WindChill(%v°F, %v mph) = %.1f°F, %v, want %.1f°F, %v", tt.tempF, tt.mph, CToF(got), ok, tt.wantF, tt.ok)
+		}
+	}
+}
+
+func TestWetBulb(t *testing.T) {
+	// Stull (2011) gives 13.7°C at 20°C and 50%.
+	if got := WetBulb(20, 50); math.Abs(got-13.7) > 0.1 {
+		t.Errorf("WetBulb(20, 50) = %.2f, want 13.7", got)
+	}
+}
+
+func TestVaporPressureAndHumidity(t *testing.T) {
+	e := VaporPressure(10)
+	if math.Abs(e-12.27) > 0.02 {
+		t.Errorf("VaporPressure(10) = %.2f, want 12.27", e)
+	}
+	if got := AbsoluteHumidity(20, e); math.Abs(got-9.07) > 0.02 {
+		t.Errorf("AbsoluteHumidity(20, %.2f) = %.2f, want 9.07", e, got)
+	}
+	if got := RelativeHumidity(20, 20); math.Abs(got-100) > 0.01 {
+		t.Errorf("RelativeHumidity(20, 20) = %.2f, want 100", got)
+	}
+}
+
+func TestDerive(t *testing.T) {
+	hot := Observation{
+		Temperature:      NullFloat64{Value: floatPtr(35)},
+		Dewpoint:         NullFloat64{Value: floatPtr(20)},
+		RelativeHumidity: NullFloat64{Value: floatPtr(41)},
+		WindSpeed:        NullFloat64{Value: floatPtr(10)},
+	}
+	d := Derive(hot)
+	if d.HeatIndex == nil || d.WindChill != nil {
+		t.Fatalf("Derive(hot) = %+v, want heat index only", d)
+	}
+	if *d.FeelsLike != *d.HeatIndex {
+		t.Errorf("FeelsLike = %v, want heat index %v", *d.FeelsLike, *d.HeatIndex)
+	}
+	if d.WetBulb == nil || d.VaporPressure == nil || d.AbsoluteHumidity == nil {
+		t.Errorf("Derive(hot) = %+v, want wet bulb and humidity values", d)
+	}
+
+	cold := Observation{
+		Temperature: NullFloat64{Value: floatPtr(-10)},
+		Dewpoint:    NullFloat64{Value: floatPtr(-15)},
+		WindSpeed:   NullFloat64{Value: floatPtr(30)},
+	}
+	d = Derive(cold)
+	if d.WindChill == nil || d.HeatIndex != nil || *d.FeelsLike != *d.WindChill {
+		t.Errorf("Derive(cold) = %+v, want wind chill as feels-like", d)
+	}
+	if d.WetBulb == nil {
+		t.Error("Derive(cold) should compute wet bulb from dewpoint-derived humidity")
+	}
+
+	mild := Observation{Temperature: NullFloat64{Value: floatPtr(18)}}
+	d = Derive(mild)
+	if d.FeelsLike == nil || *d.FeelsLike != 18 {
+		t.Errorf("Derive(mild).FeelsLike = %v, want temperature", d.FeelsLike)
+	}
+	if d.HeatIndex != nil || d.WindChill != nil || d.WetBulb != nil || d.VaporPressure != nil {
+		t.Errorf("Derive(mild) = %+v, want only feels-like", d)
+	}
+
+	if d := Derive(Observation{}); d != (Derived{}) {
+		t.Errorf("Derive(empty) = %+v, want all nil", d)
+	}
+}
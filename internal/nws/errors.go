@@ -0,0 +1,81 @@
+package nws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors for the kinds of API failure callers handle
+// differently. Use errors.Is to test an error returned by Client.
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrUnsupportedPoint = errors.New("point not supported by the NWS API")
+	ErrRateLimited      = errors.New("rate limited by the NWS API")
+	ErrUpstream         = errors.New("NWS API unavailable")
+)
+
+// APIError is a non-200 response from the NWS API. When the API returns
+// an application/problem+json body its fields are decoded; otherwise Body
+// holds the raw response.
+type APIError struct {
+	StatusCode    int    `json:"-"`
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Status        int    `json:"status"`
+	Detail        string `json:"detail"`
+	Instance      string `json:"instance"`
+	CorrelationID string `json:"correlationId"`
+	Body          string `json:"-"`
+}
+
+func newAPIError(status int, body []byte) *APIError {
+	e := &APIError{StatusCode: status}
+	if err := json.Unmarshal(body, e); err != nil || (e.Title == "" && e.Detail == "") {
+		*e = APIError{StatusCode: status, Body: strings.TrimSpace(string(body))}
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("HTTP %d", e.StatusCode)
+	switch {
+	case e.Title != "" && e.Detail != "":
+		msg += ": " + e.Title + ": " + e.Detail
+	case e.Title != "" || e.Detail != "":
+		msg += ": " + e.Title + e.Detail
+	case e.Body != "":
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+// ProblemType returns the last path segment of the problem type URI,
+// e.g. "InvalidPoint".
+func (e *APIError) ProblemType() string {
+	return e.Type[strings.LastIndex(e.Type, "/")+1:]
+}
+
+// Kind returns the sentinel error describing this failure, or nil for
+// other client errors.
+func (e *APIError) Kind() error {
+	switch {
+	case e.ProblemType() == "InvalidPoint":
+		return ErrUnsupportedPoint
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrUpstream
+	}
+	return nil
+}
+
+// Is lets errors.Is match an APIError against the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	kind := e.Kind()
+	return kind != nil && kind == target
+}
@@ -0,0 +1,90 @@
+package nws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewAPIError_Problem(t *testing.T) {
+	body := `{
+		"correlationId": "1a2b3c",
+		"title": "Invalid Point",
+		"type": "https://api.weather.gov/problems/InvalidPoint",
+		"status": 404,
+		"detail": "Unable to provide data for requested point 51.5072,-0.1276",
+		"instance": "https://api.weather.gov/requests/1a2b3c"
+	}`
+	e := newAPIError(404, []byte(body))
+	if e.StatusCode != 404 || e.Status != 404 || e.CorrelationID != "1a2b3c" {
+		t.Errorf("unexpected APIError %+v", e)
+	}
+	if e.ProblemType() != "InvalidPoint" {
+		t.Errorf("ProblemType() = %q, want InvalidPoint", e.ProblemType())
+	}
+	want := "HTTP 404: Invalid Point: Unable to provide data for requested point 51.5072,-0.1276"
+	if e.Error() != want {
+		t.Errorf("Error() = %q, want %q", e.Error(), want)
+	}
+	if !errors.Is(e, ErrUnsupportedPoint) || errors.Is(e, ErrNotFound) {
+		t.Error("InvalidPoint should match ErrUnsupportedPoint only")
+	}
+}
+
+func TestNewAPIError_RawBody(t *testing.T) {
+	e := newAPIError(502, []byte("<html>Bad Gateway</html>\n"))
+	if e.Body != "<html>Bad Gateway</html>" || e.Title != "" {
+		t.Errorf("unexpected APIError %+v", e)
+	}
+	if e.Error() != "HTTP 502: <html>Bad Gateway</html>" {
+		t.Errorf("Error() = %q", e.Error())
+	}
+	if e2 := newAPIError(500, nil); e2.Error() != "HTTP 500" {
+		t.Errorf("Error() with empty body = %q", e2.Error())
+	}
+}
+
+func TestAPIError_Kind(t *testing.T) {
+	tests := []struct {
+		status int
+		typ    string
+		want   error
+	}{
+		{404, "https://api.weather.gov/problems/NotFound", ErrNotFound},
+		{404, "https://api.weather.gov/problems/InvalidPoint", ErrUnsupportedPoint},
+		{400, "https://api.weather.gov/problems/InvalidPoint", ErrUnsupportedPoint},
+		{429, "", ErrRateLimited},
+		{500, "https://api.weather.gov/problems/UnexpectedProblem", ErrUpstream},
+		{503, "", ErrUpstream},
+		{400, "https://api.weather.gov/problems/BadRequest", nil},
+	}
+	for _, tt := range tests {
+		e := &APIError{StatusCode: tt.status, Type: tt.typ}
+		if got := e.Kind(); got != tt.want {
+			t.Errorf("Kind(%d, %q) = %v, want %v", tt.status, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestClientGet_ReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"title":"Not Found","detail":"Station KXYZ not found","status":404}`))
+	}))
+	defer server.Close()
+
+	_, err := testClient(server.URL).Station(context.Background(), "KXYZ")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %v is not an *APIError", err)
+	}
+	if apiErr.Detail != "Station KXYZ not found" {
+		t.Errorf("Detail = %q", apiErr.Detail)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("errors.Is(err, ErrNotFound) = false")
+	}
+}
@@ -0,0 +1,55 @@
+package nws
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TemperatureC returns the period's temperature in °C, whichever unit it
+// was issued in.
+func (p ForecastPeriod) TemperatureC() float64 {
+	t := float64(p.Temperature)
+	if strings.EqualFold(p.TemperatureUnit, "F") {
+		return (t - 32) * 5 / 9
+	}
+	return t
+}
+
+// WindSpeedRange parses the period's wind speed text, such as "10 mph" or
+// "5 to 15 km/h", returning the lowest and highest speeds in km/h.
+func (p ForecastPeriod) WindSpeedRange() (lo, hi float64, ok bool) {
+	fields := strings.Fields(p.WindSpeed)
+	if len(fields) < 2 {
+		return 0, 0, false
+	}
+
+	factor := 1.0
+	switch fields[len(fields)-1] {
+	case "mph":
+		factor = 1.609344
+	case "km/h":
+	case "kt":
+		factor = 1.852
+	default:
+		return 0, 0, false
+	}
+
+	var speeds []float64
+	for _, f := range fields[:len(fields)-1] {
+		if f == "to" {
+			continue
+		}
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		speeds = append(speeds, v*factor)
+	}
+	switch len(speeds) {
+	case 1:
+		return speeds[0], speeds[0], true
+	case 2:
+		return speeds[0], speeds[1], true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,47 @@
+package nws
+
+import (
+	"math"
+	"testing"
+)
+
+func TestForecastPeriod_TemperatureC(t *testing.T) {
+	tests := []struct {
+		temp int
+		unit string
+		want float64
+	}{
+		{32, "F", 0},
+		{212, "F", 100},
+		{20, "C", 20},
+	}
+	for _, tt := range tests {
+		p := ForecastPeriod{Temperature: tt.temp, TemperatureUnit: tt.unit}
+		if got := p.TemperatureC(); math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("TemperatureC() for %d%s = %v, want %v", tt.temp, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestForecastPeriod_WindSpeedRange(t *testing.T) {
+	tests := []struct {
+		speed  string
+		lo, hi float64
+		ok     bool
+	}{
+		{"10 mph", 16.09, 16.09, true},
+		{"5 to 15 mph", 8.05, 24.14, true},
+		{"20 km/h", 20, 20, true},
+		{"10 kt", 18.52, 18.52, true},
+		{"", 0, 0, false},
+		{"calm", 0, 0, false},
+		{"10 furlongs", 0, 0, false},
+		{"a to b mph", 0, 0, false},
+	}
+	for _, tt := range tests {
+		lo, hi, ok := ForecastPeriod{WindSpeed: tt.speed}.WindSpeedRange()
+		if ok != tt.ok || math.Abs(lo-tt.lo) > 0.01 || math.Abs(hi-tt.hi) > 0.01 {
+			t.Errorf("WindSpeedRange(%q) = %v, %v, %v, want %v, %v, %v", tt.speed, lo, hi, ok, tt.lo, tt.hi, tt.ok)
+		}
+	}
+}
@@ -1,10 +1,11 @@
 package nws
 
 import (
-	"fmt"
 	"math"
 	"strings"
 	"time"
+
+	"lastwind/internal/units"
 )
 
 func CToF(c float64) float64 {
@@ -33,7 +34,9 @@ func CompassDir(deg *float64) string {
 	return dirs[idx]
 }
 
-func FormatWind(dir, speed, gust *float64) string {
+// FormatWind formats a wind direction, speed and gust in km/h as e.g.
+// "NW 12 G 25", with speeds in the given unit.
+func FormatWind(dir, speed, gust *float64, u units.Speed) string {
 	hasSpeed := speed != nil && *speed > 0
 	hasGust := gust != nil && *gust > 0
 
@@ -49,20 +52,26 @@ func FormatWind(dir, speed, gust *float64) string {
 		parts = append(parts, "Vrbl")
 	}
 	if hasSpeed {
-		parts = append(parts, fmt.Sprintf("%.0f", KmhToMph(*speed)))
+		parts = append(parts, u.Format(*speed))
 	}
 	if hasGust {
-		parts = append(parts, fmt.Sprintf("G %.0f", KmhToMph(*gust)))
+		parts = append(parts, "G "+u.Format(*gust))
 	}
 	return strings.Join(parts, " ")
 }
 
 func FormatTime(ts string) string {
+	return FormatTimeIn(ts, time.Local)
+}
+
+// FormatTimeIn formats an RFC 3339 timestamp in loc, returning it
+// unchanged if it doesn't parse.
+func FormatTimeIn(ts string, loc *time.Location) string {
 	t, err := time.Parse(time.RFC3339, ts)
 	if err != nil {
 		return ts
 	}
-	return t.Local().Format("Jan 02 15:04")
+	return t.In(loc).Format("Jan 02 15:04")
 }
 
 func FmtVal(v *float64, fn func(float64) string) string {
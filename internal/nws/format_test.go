@@ -3,6 +3,9 @@ package nws
 import (
 	"math"
 	"testing"
+	"time"
+
+	"lastwind/internal/units"
 )
 
 func floatPtr(f float64) *float64 {
@@ -100,7 +103,7 @@ func TestFormatWind(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := FormatWind(tt.dir, tt.speed, tt.gust)
+			got := FormatWind(tt.dir, tt.speed, tt.gust, units.MPH)
 			if got != tt.want {
 				t.Errorf("FormatWind() = %q, want %q", got, tt.want)
 			}
@@ -122,6 +125,25 @@ func TestFormatTime(t *testing.T) {
 	}
 }
 
+func TestFormatTimeIn(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+	tests := []struct {
+		loc  *time.Location
+		want string
+	}{
+		{time.UTC, "Feb 17 18:15"},
+		{denver, "Feb 17 11:15"},
+	}
+	for _, tt := range tests {
+		if got := FormatTimeIn("2026-02-17T18:15:00+00:00", tt.loc); got != tt.want {
+			t.Errorf("FormatTimeIn(%s) = %q, want %q", tt.loc, got, tt.want)
+		}
+	}
+}
+
 func TestFmtVal(t *testing.T) {
 	// nil returns dash
 	got := FmtVal(nil, func(v float64) string { return "x" })
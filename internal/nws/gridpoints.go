@@ -0,0 +1,199 @@
+package nws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"lastwind/internal/units"
+)
+
+// GridpointResponse is the raw forecast grid returned by
+// /gridpoints/{wfo}/{x},{y}.
+type GridpointResponse struct {
+	Properties GridpointProperties `json:"properties"`
+}
+
+// GridpointProperties holds the grid metadata and every numeric forecast
+// layer, keyed by its API name (windSpeed, skyCover, mixingHeight, ...).
+// Non-numeric layers such as weather and hazards are not decoded.
+type GridpointProperties struct {
+	UpdateTime string
+	GridID     string
+	GridX      int
+	GridY      int
+	Layers     map[string]GridLayer
+}
+
+func (p *GridpointProperties) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var meta struct {
+		UpdateTime string `json:"updateTime"`
+		GridID     string `json:"gridId"`
+		GridX      int    `json:"gridX"`
+		GridY      int    `json:"gridY"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return err
+	}
+	p.UpdateTime = meta.UpdateTime
+	p.GridID = meta.GridID
+	p.GridX = meta.GridX
+	p.GridY = meta.GridY
+
+	p.Layers = make(map[string]GridLayer)
+	for name, msg := range raw {
+		var layer GridLayer
+		if err := json.Unmarshal(msg, &layer); err != nil || layer.Values == nil {
+			continue
+		}
+		p.Layers[name] = layer
+	}
+	return nil
+}
+
+// LayerNames returns the decoded layer names in alphabetical order.
+func (p GridpointProperties) LayerNames() []string {
+	names := make([]string, 0, len(p.Layers))
+	for name := range p.Layers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GridLayer is a single forecast layer: a unit of measure and a series of
+// values, each valid over an ISO 8601 interval.
+type GridLayer struct {
+	UOM    string      `json:"uom"`
+	Values []GridValue `json:"values"`
+}
+
+type GridValue struct {
+	ValidTime string   `json:"validTime"`
+	Value     *float64 `json:"value"`
+}
+
+// HourlyValue is a layer value for the hour starting at Time.
+type HourlyValue struct {
+	Time  time.Time
+	Value *float64
+}
+
+// Hourly expands the layer's intervals into one value per hour.
+func (l GridLayer) Hourly() ([]HourlyValue, error) {
+	var out []HourlyValue
+	for _, v := range l.Values {
+		start, dur, err := ParseValidTime(v.ValidTime)
+		if err != nil {
+			return nil, err
+		}
+		for t := start; t.Before(start.Add(dur)); t = t.Add(time.Hour) {
+			out = append(out, HourlyValue{Time: t, Value: v.Value})
+		}
+	}
+	return out, nil
+}
+
+// ParseValidTime splits an ISO 8601 interval such as
+// "2026-02-17T10:00:00+00:00/PT3H" into its start time and duration.
+func ParseValidTime(s string) (time.Time, time.Duration, error) {
+	startStr, durStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return time.Time{}, 0, fmt.Errorf("invalid validTime %q: missing duration", s)
+	}
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid validTime %q: %w", s, err)
+	}
+	dur, err := ParseISODuration(durStr)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid validTime %q: %w", s, err)
+	}
+	return start, dur, nil
+}
+
+// ParseISODuration parses the day/hour/minute/second subset of ISO 8601
+// durations used by the NWS API (e.g. PT3H, P1D, P1DT6H, PT30M).
+func ParseISODuration(s string) (time.Duration, error) {
+	rest, ok := strings.CutPrefix(s, "P")
+	if !ok || rest == "" || strings.HasSuffix(rest, "T") {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var total time.Duration
+	inTime := false
+	num := ""
+	for _, r := range rest {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			if inTime || num != "" {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		num = ""
+
+		var unit time.Duration
+		switch {
+		case r == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case r == 'H' && inTime:
+			unit = time.Hour
+		case r == 'M' && inTime:
+			unit = time.Minute
+		case r == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(n) * unit
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return total, nil
+}
+
+// ConvertUOM converts a value in the given WMO unit of measure to the
+// display unit chosen by sys, returning the converted value and its unit
+// label. Unknown units are returned unchanged.
+func ConvertUOM(v float64, uom string, sys units.System) (float64, string) {
+	switch strings.TrimPrefix(uom, "wmoUnit:") {
+	case "degC":
+		return sys.Temperature.Convert(v), sys.Temperature.Label()
+	case "km_h-1":
+		return sys.Speed.Convert(v), sys.Speed.Label()
+	case "m_s-1":
+		return sys.Speed.Convert(v * 3.6), sys.Speed.Label()
+	case "mm":
+		return sys.Distance.Precipitation(v)
+	case "m":
+		return sys.Distance.Height(v)
+	case "Pa":
+		return sys.Pressure.Convert(v), sys.Pressure.Label()
+	case "percent":
+		return v, "%"
+	case "degree_(angle)":
+		return v, "°"
+	}
+	return v, strings.TrimPrefix(uom, "wmoUnit:")
+}
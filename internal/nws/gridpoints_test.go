@@ -0,0 +1,172 @@
+package nws
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"lastwind/internal/units"
+)
+
+const gridpointJSON = `{
+	"properties": {
+		"updateTime": "2026-02-17T09:12:44+00:00",
+		"gridId": "BOU",
+		"gridX": 62,
+		"gridY": 60,
+		"elevation": {"unitCode": "wmoUnit:m", "value": 1655.064},
+		"windSpeed": {
+			"uom": "wmoUnit:km_h-1",
+			"values": [
+				{"validTime": "2026-02-17T10:00:00+00:00/PT3H", "value": 24.1},
+				{"validTime": "2026-02-17T13:00:00+00:00/PT1H", "value": null}
+			]
+		},
+		"skyCover": {
+			"uom": "wmoUnit:percent",
+			"values": [{"validTime": "2026-02-17T10:00:00+00:00/P1D", "value": 40}]
+		},
+		"weather": {
+			"values": [{"validTime": "2026-02-17T10:00:00+00:00/PT6H", "value": [{"coverage": null}]}]
+		}
+	}
+}`
+
+func TestGridpointResponse_UnmarshalJSON(t *testing.T) {
+	var resp GridpointResponse
+	if err := json.Unmarshal([]byte(gridpointJSON), &resp); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	p := resp.Properties
+	if p.GridID != "BOU" || p.GridX != 62 || p.GridY != 60 {
+		t.Errorf("grid = %s %d,%d, want BOU 62,60", p.GridID, p.GridX, p.GridY)
+	}
+
+	names := p.LayerNames()
+	if len(names) != 2 || names[0] != "skyCover" || names[1] != "windSpeed" {
+		t.Errorf("LayerNames() = %v, want [skyCover windSpeed]", names)
+	}
+
+	ws := p.Layers["windSpeed"]
+	if ws.UOM != "wmoUnit:km_h-1" || len(ws.Values) != 2 {
+		t.Fatalf("windSpeed layer = %+v", ws)
+	}
+	if ws.Values[1].Value != nil {
+		t.Errorf("expected null value to decode as nil")
+	}
+}
+
+func TestGridLayer_Hourly(t *testing.T) {
+	var resp GridpointResponse
+	if err := json.Unmarshal([]byte(gridpointJSON), &resp); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	hourly, err := resp.Properties.Layers["windSpeed"].Hourly()
+	if err != nil {
+		t.Fatalf("Hourly() error = %v", err)
+	}
+	if len(hourly) != 4 {
+		t.Fatalf("len(Hourly()) = %d, want 4", len(hourly))
+	}
+	start := time.Date(2026, 2, 17, 10, 0, 0, 0, time.UTC)
+	for i, h := range hourly {
+		if !h.Time.Equal(start.Add(time.Duration(i) * time.Hour)) {
+			t.Errorf("hourly[%d].Time = %v", i, h.Time)
+		}
+	}
+	if hourly[2].Value == nil || *hourly[2].Value != 24.1 {
+		t.Errorf("hourly[2].Value unexpected")
+	}
+	if hourly[3].Value != nil {
+		t.Errorf("hourly[3].Value should be nil")
+	}
+
+	bad := GridLayer{Values: []GridValue{{ValidTime: "garbage"}}}
+	if _, err := bad.Hourly(); err == nil {
+		t.Error("Hourly() expected error for invalid validTime")
+	}
+}
+
+func TestParseValidTime(t *testing.T) {
+	start, dur, err := ParseValidTime("2026-02-17T10:00:00+00:00/PT3H")
+	if err != nil {
+		t.Fatalf("ParseValidTime() error = %v", err)
+	}
+	if !start.Equal(time.Date(2026, 2, 17, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("start = %v", start)
+	}
+	if dur != 3*time.Hour {
+		t.Errorf("dur = %v, want 3h", dur)
+	}
+
+	for _, in := range []string{"2026-02-17T10:00:00+00:00", "bad/PT1H", "2026-02-17T10:00:00+00:00/3H"} {
+		if _, _, err := ParseValidTime(in); err == nil {
+			t.Errorf("ParseValidTime(%q) expected error", in)
+		}
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"PT1H", time.Hour},
+		{"PT3H", 3 * time.Hour},
+		{"P1D", 24 * time.Hour},
+		{"P1DT6H", 30 * time.Hour},
+		{"PT30M", 30 * time.Minute},
+		{"PT1H30M15S", time.Hour + 30*time.Minute + 15*time.Second},
+		{"P1W", 7 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseISODuration(tt.input)
+		if err != nil {
+			t.Errorf("ParseISODuration(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseISODuration(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "P", "T1H", "PT", "P1H", "PT1D", "PT5", "P1M"} {
+		if _, err := ParseISODuration(in); err == nil {
+			t.Errorf("ParseISODuration(%q) expected error", in)
+		}
+	}
+}
+
+func TestConvertUOM(t *testing.T) {
+	tests := []struct {
+		v        float64
+		uom      string
+		sys      units.System
+		want     float64
+		wantUnit string
+	}{
+		{0, "wmoUnit:degC", units.US, 32, "°F"},
+		{100, "wmoUnit:km_h-1", units.US, 62.1371, "mph"},
+		{10, "wmoUnit:m_s-1", units.US, 22.3694, "mph"},
+		{25.4, "wmoUnit:mm", units.US, 1, "in"},
+		{1000, "wmoUnit:m", units.US, 3280.84, "ft"},
+		{101325, "wmoUnit:Pa", units.US, 29.92, "inHg"},
+		{40, "wmoUnit:percent", units.US, 40, "%"},
+		{270, "wmoUnit:degree_(angle)", units.US, 270, "°"},
+		{5, "wmoUnit:unknown", units.US, 5, "unknown"},
+		{20, "wmoUnit:degC", units.Metric, 20, "°C"},
+		{37.04, "wmoUnit:km_h-1", units.Aviation, 20, "kt"},
+		{10, "wmoUnit:m_s-1", units.SI, 10, "m/s"},
+		{5, "wmoUnit:mm", units.Metric, 5, "mm"},
+		{1000, "wmoUnit:m", units.Metric, 1000, "m"},
+		{101325, "wmoUnit:Pa", units.Metric, 1013.25, "hPa"},
+	}
+	for _, tt := range tests {
+		got, unit := ConvertUOM(tt.v, tt.uom, tt.sys)
+		if math.Abs(got-tt.want) > 0.01 || unit != tt.wantUnit {
+			t.Errorf("ConvertUOM(%v, %q, %v) = %v %q, want %v %q", tt.v, tt.uom, tt.sys, got, unit, tt.want, tt.wantUnit)
+		}
+	}
+}
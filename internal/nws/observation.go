@@ -0,0 +1,173 @@
+package nws
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UnmarshalJSON decodes an observation and converts each value from its
+// unitCode to the units documented on Observation, so callers don't need
+// to assume what a station reported in.
+func (o *Observation) UnmarshalJSON(data []byte) error {
+	type plain Observation
+	if err := json.Unmarshal(data, (*plain)(o)); err != nil {
+		return err
+	}
+
+	for _, f := range []struct {
+		v    *NullFloat64
+		unit string
+	}{
+		{&o.Elevation, "m"},
+		{&o.Temperature, "degC"},
+		{&o.Dewpoint, "degC"},
+		{&o.WindSpeed, "km_h-1"},
+		{&o.WindGust, "km_h-1"},
+		{&o.Visibility, "m"},
+		{&o.Barometer, "Pa"},
+		{&o.SeaLevelPressure, "Pa"},
+		{&o.WindChill, "degC"},
+		{&o.HeatIndex, "degC"},
+		{&o.MaxTemperatureLast24Hours, "degC"},
+		{&o.MinTemperatureLast24Hours, "degC"},
+		{&o.PrecipitationLastHour, "mm"},
+		{&o.PrecipitationLast3Hours, "mm"},
+		{&o.PrecipitationLast6Hours, "mm"},
+	} {
+		f.v.convertTo(f.unit)
+	}
+	for i := range o.CloudLayers {
+		o.CloudLayers[i].Base.convertTo("m")
+	}
+	return nil
+}
+
+// unitScales gives the size of each linear unit in its dimension's base
+// unit (metres, metres per second or pascals).
+var unitScales = map[string]struct {
+	dimension string
+	scale     float64
+}{
+	"m":         {"length", 1},
+	"km":        {"length", 1000},
+	"cm":        {"length", 0.01},
+	"mm":        {"length", 0.001},
+	"ft":        {"length", 0.3048},
+	"[ft_i]":    {"length", 0.3048},
+	"in":        {"length", 0.0254},
+	"[in_i]":    {"length", 0.0254},
+	"mi":        {"length", 1609.344},
+	"[mi_i]":    {"length", 1609.344},
+	"m_s-1":     {"speed", 1},
+	"km_h-1":    {"speed", 1 / 3.6},
+	"kt":        {"speed", 1852.0 / 3600},
+	"kn":        {"speed", 1852.0 / 3600},
+	"[kn_i]":    {"speed", 1852.0 / 3600},
+	"mi_h-1":    {"speed", 1609.344 / 3600},
+	"Pa":        {"pressure", 1},
+	"hPa":       {"pressure", 100},
+	"kPa":       {"pressure", 1000},
+	"mbar":      {"pressure", 100},
+	"[in_i'Hg]": {"pressure", 3386.389},
+	"inHg":      {"pressure", 3386.389},
+}
+
+// convertTo converts the value from its unitCode to unit, a bare WMO unit
+// such as "degC" or "km_h-1", and updates UnitCode to match. Values
+// without a unit code are assumed to already be in unit; values in a unit
+// that can't be converted are left as reported.
+func (n *NullFloat64) convertTo(unit string) {
+	from := unitName(n.UnitCode)
+	if n.Value == nil || from == "" || from == unit {
+		return
+	}
+
+	v, err := convertUnit(*n.Value, from, unit)
+	if err != nil {
+		return
+	}
+	n.Value = &v
+	n.UnitCode = "wmoUnit:" + unit
+}
+
+// unitName strips the namespace from a unit code, e.g. "wmoUnit:degC".
+func unitName(code string) string {
+	if i := strings.LastIndex(code, ":"); i >= 0 {
+		return code[i+1:]
+	}
+	return code
+}
+
+func convertUnit(v float64, from, to string) (float64, error) {
+	if c, ok := toCelsius(v, from); ok {
+		switch to {
+		case "degC":
+			return c, nil
+		case "degF":
+			return CToF(c), nil
+		case "K":
+			return c + 273.15, nil
+		}
+	}
+
+	f, fromOK := unitScales[from]
+	t, toOK := unitScales[to]
+	if !fromOK || !toOK || f.dimension != t.dimension {
+		return 0, fmt.Errorf("cannot convert %s to %s", from, to)
+	}
+	return v * f.scale / t.scale, nil
+}
+
+func toCelsius(v float64, unit string) (float64, bool) {
+	switch unit {
+	case "degC":
+		return v, true
+	case "degF":
+		return fToC(v), true
+	case "K":
+		return v - 273.15, true
+	}
+	return 0, false
+}
+
+// Ceiling returns the height in metres of the lowest broken, overcast or
+// obscured (vertical visibility) cloud layer, or nil if there is none.
+func (o Observation) Ceiling() *float64 {
+	var ceiling *float64
+	for _, l := range o.CloudLayers {
+		switch l.Amount {
+		case "BKN", "OVC", "VV":
+			if l.Base.Value != nil && (ceiling == nil || *l.Base.Value < *ceiling) {
+				v := *l.Base.Value
+				ceiling = &v
+			}
+		}
+	}
+	return ceiling
+}
+
+// SkyCondition summarises the cloud layers in METAR form, e.g.
+// "FEW050 BKN120", with bases in hundreds of feet.
+func (o Observation) SkyCondition() string {
+	var parts []string
+	for _, l := range o.CloudLayers {
+		if l.Base.Value == nil {
+			parts = append(parts, l.Amount)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s%03.0f", l.Amount, *l.Base.Value*3.28084/100))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Weather returns the present weather as METAR codes, e.g. "-SN BR".
+func (o Observation) Weather() string {
+	parts := make([]string, 0, len(o.PresentWeather))
+	for _, w := range o.PresentWeather {
+		if w.RawString != "" {
+			parts = append(parts, w.RawString)
+		}
+	}
+	return strings.Join(parts, " ")
+}
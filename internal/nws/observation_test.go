@@ -0,0 +1,145 @@
+package nws
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+const fullObservationJSON = `{
+	"timestamp": "2026-02-17T18:15:00+00:00",
+	"rawMessage": "KDEN 171815Z 27017G26KT 3SM -SN BR FEW015 BKN040 OVC080 M02/M05 A2992",
+	"textDescription": "Light Snow and Mist",
+	"elevation": {"unitCode": "wmoUnit:m", "value": 1656},
+	"temperature": {"unitCode": "wmoUnit:degF", "value": 28.4, "qualityControl": "V"},
+	"dewpoint": {"unitCode": "wmoUnit:degC", "value": -5, "qualityControl": "V"},
+	"windSpeed": {"unitCode": "wmoUnit:m_s-1", "value": 10},
+	"windGust": {"unitCode": "wmoUnit:kn", "value": 26},
+	"visibility": {"unitCode": "wmoUnit:m", "value": 4830},
+	"barometricPressure": {"unitCode": "wmoUnit:hPa", "value": 1013.2},
+	"seaLevelPressure": {"unitCode": "wmoUnit:Pa", "value": 101500},
+	"heatIndex": {"unitCode": "wmoUnit:degC", "value": null},
+	"maxTemperatureLast24Hours": {"unitCode": "wmoUnit:K", "value": 278.15},
+	"minTemperatureLast24Hours": {"unitCode": "wmoUnit:degC", "value": -8},
+	"precipitationLastHour": {"unitCode": "wmoUnit:m", "value": 0.0005},
+	"precipitationLast3Hours": {"unitCode": "wmoUnit:mm", "value": 1.3},
+	"precipitationLast6Hours": {"unitCode": "wmoUnit:furlong", "value": 2},
+	"presentWeather": [
+		{"intensity": "light", "modifier": null, "weather": "snow", "rawString": "-SN"},
+		{"intensity": null, "modifier": null, "weather": "fog_mist", "rawString": "BR"}
+	],
+	"cloudLayers": [
+		{"base": {"unitCode": "wmoUnit:m", "value": 460}, "amount": "FEW"},
+		{"base": {"unitCode": "wmoUnit:m", "value": 1220}, "amount": "BKN"},
+		{"base": {"unitCode": "wmoUnit:m", "value": 2440}, "amount": "OVC"}
+	]
+}`
+
+func TestObservation_UnmarshalJSON_Full(t *testing.T) {
+	var o Observation
+	if err := json.Unmarshal([]byte(fullObservationJSON), &o); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		v    NullFloat64
+		want float64
+		unit string
+	}{
+		{"elevation", o.Elevation, 1656, "wmoUnit:m"},
+		{"temperature", o.Temperature, -2, "wmoUnit:degC"},
+		{"dewpoint", o.Dewpoint, -5, "wmoUnit:degC"},
+		{"windSpeed", o.WindSpeed, 36, "wmoUnit:km_h-1"},
+		{"windGust", o.WindGust, 48.152, "wmoUnit:km_h-1"},
+		{"barometricPressure", o.Barometer, 101320, "wmoUnit:Pa"},
+		{"seaLevelPressure", o.SeaLevelPressure, 101500, "wmoUnit:Pa"},
+		{"maxTemperatureLast24Hours", o.MaxTemperatureLast24Hours, 5, "wmoUnit:degC"},
+		{"minTemperatureLast24Hours", o.MinTemperatureLast24Hours, -8, "wmoUnit:degC"},
+		{"precipitationLastHour", o.PrecipitationLastHour, 0.5, "wmoUnit:mm"},
+		{"precipitationLast3Hours", o.PrecipitationLast3Hours, 1.3, "wmoUnit:mm"},
+		{"precipitationLast6Hours", o.PrecipitationLast6Hours, 2, "wmoUnit:furlong"},
+		{"cloud base", o.CloudLayers[1].Base, 1220, "wmoUnit:m"},
+	}
+	for _, tt := range tests {
+		if tt.v.Value == nil {
+			t.Errorf("%s is nil", tt.name)
+			continue
+		}
+		if math.Abs(*tt.v.Value-tt.want) > 0.01 || tt.v.UnitCode != tt.unit {
+			t.Errorf("%s = %v %s, want %v %s", tt.name, *tt.v.Value, tt.v.UnitCode, tt.want, tt.unit)
+		}
+	}
+
+	if o.HeatIndex.Value != nil {
+		t.Errorf("HeatIndex = %v, want nil", *o.HeatIndex.Value)
+	}
+	if o.Temperature.QualityControl != "V" {
+		t.Errorf("QualityControl = %q, want V", o.Temperature.QualityControl)
+	}
+	if o.RawMessage == "" || len(o.PresentWeather) != 2 || o.PresentWeather[0].Intensity != "light" {
+		t.Errorf("unexpected raw message or present weather: %q %+v", o.RawMessage, o.PresentWeather)
+	}
+	if got := o.Weather(); got != "-SN BR" {
+		t.Errorf("Weather() = %q, want -SN BR", got)
+	}
+	if got := o.SkyCondition(); got != "FEW015 BKN040 OVC080" {
+		t.Errorf("SkyCondition() = %q, want FEW015 BKN040 OVC080", got)
+	}
+	if c := o.Ceiling(); c == nil || *c != 1220 {
+		t.Errorf("Ceiling() = %v, want 1220", c)
+	}
+}
+
+func TestObservation_Ceiling(t *testing.T) {
+	layer := func(amount string, base float64) CloudLayer {
+		return CloudLayer{Amount: amount, Base: NullFloat64{Value: floatPtr(base)}}
+	}
+	tests := []struct {
+		name   string
+		layers []CloudLayer
+		want   *float64
+	}{
+		{"clear", []CloudLayer{{Amount: "CLR"}}, nil},
+		{"scattered only", []CloudLayer{layer("FEW", 500), layer("SCT", 900)}, nil},
+		{"overcast", []CloudLayer{layer("SCT", 500), layer("OVC", 900)}, floatPtr(900)},
+		{"unordered", []CloudLayer{layer("OVC", 2000), layer("BKN", 1200)}, floatPtr(1200)},
+		{"vertical visibility", []CloudLayer{layer("VV", 60)}, floatPtr(60)},
+	}
+	for _, tt := range tests {
+		got := Observation{CloudLayers: tt.layers}.Ceiling()
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("%s: Ceiling() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestObservation_RoundTrip(t *testing.T) {
+	var o Observation
+	if err := json.Unmarshal([]byte(fullObservationJSON), &o); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again Observation
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatal(err)
+	}
+	if *again.Temperature.Value != *o.Temperature.Value || *again.WindSpeed.Value != *o.WindSpeed.Value {
+		t.Errorf("round trip changed values: %v %v", *again.Temperature.Value, *again.WindSpeed.Value)
+	}
+}
+
+func TestConvertUnit(t *testing.T) {
+	if _, err := convertUnit(1, "m", "Pa"); err == nil {
+		t.Error("convertUnit(m, Pa) expected error")
+	}
+	if got, err := convertUnit(32, "degF", "degC"); err != nil || got != 0 {
+		t.Errorf("convertUnit(32 degF, degC) = %v, %v", got, err)
+	}
+	if got, err := convertUnit(5280, "[ft_i]", "mi"); err != nil || math.Abs(got-1) > 0.001 {
+		t.Errorf("convertUnit(5280 ft, mi) = %v, %v", got, err)
+	}
+}
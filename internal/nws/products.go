@@ -0,0 +1,174 @@
+package nws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ProductsResponse is the list returned by
+// /products/types/{type}/locations/{location}, newest first.
+type ProductsResponse struct {
+	Graph []ProductSummary `json:"@graph"`
+}
+
+// ProductSummary identifies a text product such as a TAF or an Area
+// Forecast Discussion.
+type ProductSummary struct {
+	ID            string `json:"id"`
+	WMOID         string `json:"wmoCollectiveId"`
+	IssuingOffice string `json:"issuingOffice"`
+	IssuanceTime  string `json:"issuanceTime"`
+	ProductCode   string `json:"productCode"`
+	ProductName   string `json:"productName"`
+}
+
+// Product is a text product with its full text.
+type Product struct {
+	ProductSummary
+	ProductText string `json:"productText"`
+}
+
+// Products lists the products of a type (e.g. "TAF" or "AFD") issued for
+// a location, newest first.
+func (c *Client) Products(ctx context.Context, productType, location string) (ProductsResponse, error) {
+	var resp ProductsResponse
+	err := c.Get(ctx, "/products/types/"+url.PathEscape(productType)+"/locations/"+url.PathEscape(location), &resp)
+	return resp, err
+}
+
+// Product returns a product by its ID.
+func (c *Client) Product(ctx context.Context, id string) (Product, error) {
+	var resp Product
+	err := c.Get(ctx, "/products/"+url.PathEscape(id), &resp)
+	return resp, err
+}
+
+// LatestProduct returns the most recent product of a type issued for a
+// location. The error wraps ErrNotFound when none has been issued.
+func (c *Client) LatestProduct(ctx context.Context, productType, location string) (Product, error) {
+	list, err := c.Products(ctx, productType, location)
+	if err != nil {
+		return Product{}, err
+	}
+	if len(list.Graph) == 0 {
+		return Product{}, fmt.Errorf("no %s issued for %s: %w", productType, location, ErrNotFound)
+	}
+	return c.Product(ctx, list.Graph[0].ID)
+}
+
+// LatestTAF returns the most recent TAF for an ICAO station. The products
+// API lists some TAFs under the three-letter identifier (DEN for KDEN), so
+// that is tried too when the station has none.
+func (c *Client) LatestTAF(ctx context.Context, station string) (Product, error) {
+	station = strings.ToUpper(station)
+	p, err := c.LatestProduct(ctx, "TAF", station)
+	if errors.Is(err, ErrNotFound) && len(station) == 4 {
+		if short, shortErr := c.LatestProduct(ctx, "TAF", station[1:]); shortErr == nil {
+			return short, nil
+		}
+	}
+	return p, err
+}
+
+// Section is a part of a text product. Named sections start with a line
+// such as ".AVIATION /18Z TAFS/..." in an Area Forecast Discussion or
+// ".DAY ONE...Today and Tonight." in a Hazardous Weather Outlook: Name is
+// "AVIATION", Qualifier "/18Z TAFS/" and the text after the dots begins
+// Text. The header before the first section and the signatures after
+// "$$" are unnamed sections.
+type Section struct {
+	Name      string
+	Qualifier string
+	Text      string
+}
+
+// Sections splits a product's text into sections, dropping the "&&" lines
+// that separate them. Each section's text keeps its line breaks, with
+// leading and trailing blank lines removed.
+func (p Product) Sections() []Section {
+	var sections []Section
+	current := Section{}
+	var lines []string
+	flush := func() {
+		current.Text = strings.Trim(strings.Join(lines, "\n"), "\n")
+		if current.Name != "" || current.Text != "" {
+			sections = append(sections, current)
+		}
+		lines = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(p.ProductText, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \t")
+		switch {
+		case line == "&&":
+			continue
+		case line == "$$":
+			flush()
+			current = Section{}
+			continue
+		}
+		if name, qualifier, rest, ok := sectionHeading(line); ok {
+			flush()
+			current = Section{Name: name, Qualifier: qualifier}
+			if rest != "" {
+				lines = append(lines, rest)
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return sections
+}
+
+// sectionHeading parses a line such as ".SHORT TERM /Today through
+// Tonight/...Issued at 1025 AM MST" into its name, qualifier and text.
+func sectionHeading(line string) (name, qualifier, rest string, ok bool) {
+	if len(line) < 2 || line[0] != '.' || line[1] < 'A' || line[1] > 'Z' {
+		return "", "", "", false
+	}
+	head, rest, found := strings.Cut(line[1:], "...")
+	if !found {
+		return "", "", "", false
+	}
+	name, qualifier = head, ""
+	if i := strings.IndexAny(head, "/("); i > 0 && head[i-1] == ' ' {
+		name, qualifier = head[:i], head[i:]
+	}
+	name = strings.TrimSpace(name)
+	if name != strings.ToUpper(name) {
+		return "", "", "", false
+	}
+	return name, strings.TrimSpace(qualifier), strings.TrimSpace(rest), true
+}
+
+// FilterSections returns the named sections whose name contains any of
+// names, ignoring case, e.g. "fire" for FIRE WEATHER or "watches" for
+// "BOU WATCHES/WARNINGS/ADVISORIES".
+func FilterSections(sections []Section, names []string) []Section {
+	var matched []Section
+	for _, s := range sections {
+		for _, n := range names {
+			n = strings.ToUpper(strings.TrimSpace(n))
+			if s.Name != "" && n != "" && strings.Contains(s.Name, n) {
+				matched = append(matched, s)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// SectionNames lists the names of a product's named sections.
+func SectionNames(sections []Section) []string {
+	var names []string
+	for _, s := range sections {
+		if s.Name != "" {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
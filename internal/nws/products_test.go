@@ -0,0 +1,99 @@
+package nws
+
+import (
+	"strings"
+	"testing"
+)
+
+const afdText = `000
+FXUS65 KBOU 171725
+AFDBOU
+
+Area Forecast Discussion
+National Weather Service Denver/Boulder CO
+1025 AM MST Tue Feb 17 2026
+
+.KEY MESSAGES...
+
+- Strong west winds in the foothills through this evening.
+
+&&
+
+.SHORT TERM /Today through Wednesday/...
+Issued at 1025 AM MST Tue Feb 17 2026
+
+Mountain wave activity continues.
+Gusts to 70 mph are possible.
+
+&&
+
+.AVIATION /18Z TAFs/...Issued at 1020 AM MST Tue Feb 17 2026
+
+Westerly winds gusting to 35 kt at KDEN.
+
+&&
+
+.FIRE WEATHER...
+Humidity falls to 10 percent.
+
+&&
+
+.BOU WATCHES/WARNINGS/ADVISORIES...
+High Wind Warning until 5 PM MST this afternoon for COZ035.
+
+&&
+
+$$
+
+SHORT TERM...Smith
+AVIATION...Jones
+`
+
+func TestProduct_Sections(t *testing.T) {
+	sections := Product{ProductText: afdText}.Sections()
+	want := []struct{ name, qualifier, first string }{
+		{"", "", "000"},
+		{"KEY MESSAGES", "", "- Strong west winds in the foothills through this evening."},
+		{"SHORT TERM", "/Today through Wednesday/", "Issued at 1025 AM MST Tue Feb 17 2026"},
+		{"AVIATION", "/18Z TAFs/", "Issued at 1020 AM MST Tue Feb 17 2026"},
+		{"FIRE WEATHER", "", "Humidity falls to 10 percent."},
+		{"BOU WATCHES/WARNINGS/ADVISORIES", "", "High Wind Warning until 5 PM MST this afternoon for COZ035."},
+		{"", "", "SHORT TERM...Smith"},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("got %d sections, want %d: %q", len(sections), len(want), SectionNames(sections))
+	}
+	for i, w := range want {
+		s := sections[i]
+		first, _, _ := strings.Cut(s.Text, "\n")
+		if s.Name != w.name || s.Qualifier != w.qualifier || first != w.first {
+			t.Errorf("section %d = %q %q %q, want %q %q %q", i, s.Name, s.Qualifier, first, w.name, w.qualifier, w.first)
+		}
+	}
+	if text := sections[2].Text; !strings.HasSuffix(text, "Gusts to 70 mph are possible.") || strings.Contains(text, "&&") {
+		t.Errorf("short term text = %q", text)
+	}
+}
+
+func TestFilterSections(t *testing.T) {
+	sections := Product{ProductText: afdText}.Sections()
+	got := SectionNames(FilterSections(sections, []string{"aviation", " Fire", "watches"}))
+	if strings.Join(got, ",") != "AVIATION,FIRE WEATHER,BOU WATCHES/WARNINGS/ADVISORIES" {
+		t.Errorf("FilterSections = %q", got)
+	}
+	if got := FilterSections(sections, []string{"synopsis", ""}); len(got) != 0 {
+		t.Errorf("FilterSections(synopsis) = %+v", got)
+	}
+}
+
+func TestPointsResponse_Office(t *testing.T) {
+	var p PointsResponse
+	p.Properties.GridID = "BOU"
+	if got := p.Office(); got != "BOU" {
+		t.Errorf("Office() = %q, want the grid office", got)
+	}
+	p.Properties.CWA = "PUB"
+	if got := p.Office(); got != "PUB" {
+		t.Errorf("Office() = %q, want the CWA", got)
+	}
+}
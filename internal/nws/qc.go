@@ -0,0 +1,80 @@
+package nws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QCPolicy decides which values to trust based on their MADIS quality
+// control flag.
+type QCPolicy string
+
+const (
+	// QCOff trusts every value.
+	QCOff QCPolicy = "off"
+	// QCLenient excludes values that failed quality control (X or B).
+	QCLenient QCPolicy = "lenient"
+	// QCStrict also excludes values that were questioned (Q).
+	QCStrict QCPolicy = "strict"
+)
+
+// ParseQCPolicy parses "strict", "lenient" or "off". An empty string is
+// the lenient default.
+func ParseQCPolicy(s string) (QCPolicy, error) {
+	switch p := QCPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return QCLenient, nil
+	case QCOff, QCLenient, QCStrict:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown quality control policy %q (use strict, lenient or off)", s)
+}
+
+// Rejected reports whether the value failed quality control: X (failed
+// the gross limits check) or B (subjectively bad).
+func (n NullFloat64) Rejected() bool {
+	return n.QualityControl == "X" || n.QualityControl == "B"
+}
+
+// Suspect reports whether the value was questioned by quality control.
+func (n NullFloat64) Suspect() bool {
+	return n.QualityControl == "Q"
+}
+
+// Excludes reports whether the policy excludes a reported value.
+func (p QCPolicy) Excludes(n NullFloat64) bool {
+	if n.Value == nil {
+		return false
+	}
+	switch p {
+	case QCLenient:
+		return n.Rejected()
+	case QCStrict:
+		return n.Rejected() || n.Suspect()
+	}
+	return false
+}
+
+// Value returns the value, or nil if the policy excludes it.
+func (p QCPolicy) Value(n NullFloat64) *float64 {
+	if p.Excludes(n) {
+		return nil
+	}
+	return n.Value
+}
+
+// Mark returns a marker for the most serious exclusion among values: "!"
+// for rejected, "?" for suspect, or "" if the policy excludes none.
+func (p QCPolicy) Mark(values ...NullFloat64) string {
+	mark := ""
+	for _, n := range values {
+		if !p.Excludes(n) {
+			continue
+		}
+		if n.Rejected() {
+			return "!"
+		}
+		mark = "?"
+	}
+	return mark
+}
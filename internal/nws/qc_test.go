@@ -0,0 +1,84 @@
+package nws
+
+import "testing"
+
+func TestParseQCPolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    QCPolicy
+		wantErr bool
+	}{
+		{"", QCLenient, false},
+		{"strict", QCStrict, false},
+		{"Lenient", QCLenient, false},
+		{"off", QCOff, false},
+		{"paranoid", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseQCPolicy(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseQCPolicy(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestQCPolicy_Excludes(t *testing.T) {
+	value := func(qc string) NullFloat64 {
+		return NullFloat64{Value: floatPtr(1), QualityControl: qc}
+	}
+	tests := []struct {
+		qc                   string
+		off, lenient, strict bool
+	}{
+		{"V", false, false, false},
+		{"C", false, false, false},
+		{"S", false, false, false},
+		{"Z", false, false, false},
+		{"", false, false, false},
+		{"Q", false, false, true},
+		{"X", false, true, true},
+		{"B", false, true, true},
+	}
+	for _, tt := range tests {
+		n := value(tt.qc)
+		if got := QCOff.Excludes(n); got != tt.off {
+			t.Errorf("off.Excludes(%q) = %v", tt.qc, got)
+		}
+		if got := QCLenient.Excludes(n); got != tt.lenient {
+			t.Errorf("lenient.Excludes(%q) = %v", tt.qc, got)
+		}
+		if got := QCStrict.Excludes(n); got != tt.strict {
+			t.Errorf("strict.Excludes(%q) = %v", tt.qc, got)
+		}
+	}
+
+	if QCStrict.Excludes(NullFloat64{QualityControl: "X"}) {
+		t.Error("missing values should never be excluded")
+	}
+	if QCStrict.Value(value("X")) != nil || QCStrict.Value(value("V")) == nil {
+		t.Error("Value() should return nil only for excluded values")
+	}
+}
+
+func TestQCPolicy_Mark(t *testing.T) {
+	good := NullFloat64{Value: floatPtr(1), QualityControl: "V"}
+	suspect := NullFloat64{Value: floatPtr(1), QualityControl: "Q"}
+	bad := NullFloat64{Value: floatPtr(1), QualityControl: "X"}
+
+	tests := []struct {
+		p      QCPolicy
+		values []NullFloat64
+		want   string
+	}{
+		{QCStrict, []NullFloat64{good}, ""},
+		{QCStrict, []NullFloat64{good, suspect}, "?"},
+		{QCStrict, []NullFloat64{suspect, bad}, "!"},
+		{QCLenient, []NullFloat64{suspect}, ""},
+		{QCOff, []NullFloat64{bad}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Mark(tt.values...); got != tt.want {
+			t.Errorf("%s.Mark(%v) = %q, want %q", tt.p, tt.values, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package nws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"lastwind/internal/geo"
+)
+
+// Station is an observation station's metadata. Forecast, County and
+// FireWeatherZone are zone URLs; use ZoneID for their identifiers. When
+// decoded from JSON, Elevation is converted to metres.
+type Station struct {
+	StationIdentifier string      `json:"stationIdentifier"`
+	Name              string      `json:"name"`
+	TimeZone          string      `json:"timeZone"`
+	Elevation         NullFloat64 `json:"elevation"`
+	Forecast          string      `json:"forecast"`
+	County            string      `json:"county"`
+	FireWeatherZone   string      `json:"fireWeatherZone"`
+}
+
+// UnmarshalJSON decodes a station, converting its elevation to metres.
+func (s *Station) UnmarshalJSON(data []byte) error {
+	type plain Station
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	s.Elevation.convertTo("m")
+	return nil
+}
+
+// PointGeometry is a GeoJSON point. Coordinates are longitude, latitude.
+type PointGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// LatLon returns the point's latitude and longitude, and false if it has
+// no coordinates.
+func (g PointGeometry) LatLon() (lat, lon float64, ok bool) {
+	if len(g.Coordinates) < 2 {
+		return 0, 0, false
+	}
+	return g.Coordinates[1], g.Coordinates[0], true
+}
+
+// ZoneID returns the identifier at the end of a zone URL, e.g. "COZ039"
+// for "https://api.weather.gov/zones/forecast/COZ039".
+func ZoneID(zoneURL string) string {
+	return zoneURL[strings.LastIndex(zoneURL, "/")+1:]
+}
+
+// StationDistance is a station with its great-circle distance in metres
+// and initial bearing in degrees from a point.
+type StationDistance struct {
+	Station  StationResponse
+	Distance float64
+	Bearing  float64
+}
+
+// ByDistance measures each station from lat, lon and returns them nearest
+// first. Stations without coordinates are left out.
+func ByDistance(stations []StationResponse, lat, lon float64) []StationDistance {
+	var out []StationDistance
+	for _, s := range stations {
+		slat, slon, ok := s.Geometry.LatLon()
+		if !ok {
+			continue
+		}
+		out = append(out, StationDistance{
+			Station:  s,
+			Distance: geo.Distance(lat, lon, slat, slon),
+			Bearing:  geo.Bearing(lat, lon, slat, slon),
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Distance < out[j].Distance })
+	return out
+}
+
+// MatchStations returns the stations whose name or identifier contains
+// query, ignoring case.
+func MatchStations(stations []StationResponse, query string) []StationResponse {
+	query = strings.ToLower(query)
+	var out []StationResponse
+	for _, s := range stations {
+		if strings.Contains(strings.ToLower(s.Properties.Name), query) ||
+			strings.Contains(strings.ToLower(s.Properties.StationIdentifier), query) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// WithinRadius returns the measured stations at most radius metres away.
+func WithinRadius(stations []StationDistance, radius float64) []StationDistance {
+	var out []StationDistance
+	for _, d := range stations {
+		if d.Distance <= radius {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// StationSortKeys lists the keys accepted by SortStations.
+var StationSortKeys = []string{"distance", "id", "name", "elevation"}
+
+// SortStations sorts measured stations by distance, id, name or
+// elevation (highest first; unknown elevations last).
+func SortStations(stations []StationDistance, by string) error {
+	var less func(a, b StationDistance) bool
+	switch strings.ToLower(by) {
+	case "", "distance":
+		less = func(a, b StationDistance) bool { return a.Distance < b.Distance }
+	case "id":
+		less = func(a, b StationDistance) bool {
+			return a.Station.Properties.StationIdentifier < b.Station.Properties.StationIdentifier
+		}
+	case "name":
+		less = func(a, b StationDistance) bool {
+			return strings.ToLower(a.Station.Properties.Name) < strings.ToLower(b.Station.Properties.Name)
+		}
+	case "elevation":
+		less = func(a, b StationDistance) bool {
+			ea, eb := a.Station.Properties.Elevation.Value, b.Station.Properties.Elevation.Value
+			return ea != nil && (eb == nil || *ea > *eb)
+		}
+	default:
+		return fmt.Errorf("unknown sort key %q (use %s)", by, strings.Join(StationSortKeys, ", "))
+	}
+	sort.SliceStable(stations, func(i, j int) bool { return less(stations[i], stations[j]) })
+	return nil
+}
@@ -0,0 +1,146 @@
+package nws
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestStationResponse_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"geometry": {"type": "Point", "coordinates": [-104.65622, 39.84658]},
+		"properties": {
+			"stationIdentifier": "KDEN",
+			"name": "Denver International Airport",
+			"elevation": {"unitCode": "wmoUnit:ft", "value": 5404},
+			"timeZone": "America/Denver",
+			"forecast": "https://api.weather.gov/zones/forecast/COZ039",
+			"county": "https://api.weather.gov/zones/county/COC001",
+			"fireWeatherZone": "https://api.weather.gov/zones/fire/COZ239"
+		}
+	}`
+	var resp StationResponse
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	s := resp.Properties
+	if s.StationIdentifier != "KDEN" || s.TimeZone != "America/Denver" {
+		t.Errorf("station = %+v", s)
+	}
+	if s.Elevation.Value == nil || math.Abs(*s.Elevation.Value-1647.1) > 0.1 {
+		t.Errorf("Elevation = %v, want 1647.1 m", s.Elevation.Value)
+	}
+	if got := ZoneID(s.Forecast); got != "COZ039" {
+		t.Errorf("ZoneID(Forecast) = %q, want COZ039", got)
+	}
+	if got := ZoneID(s.FireWeatherZone); got != "COZ239" {
+		t.Errorf("ZoneID(FireWeatherZone) = %q, want COZ239", got)
+	}
+
+	lat, lon, ok := resp.Geometry.LatLon()
+	if !ok || lat != 39.84658 || lon != -104.65622 {
+		t.Errorf("LatLon() = %v, %v, %v", lat, lon, ok)
+	}
+	if _, _, ok := (PointGeometry{}).LatLon(); ok {
+		t.Error("LatLon() of empty geometry should not be ok")
+	}
+}
+
+func TestByDistance(t *testing.T) {
+	station := func(id string, coords ...float64) StationResponse {
+		var s StationResponse
+		s.Properties.StationIdentifier = id
+		s.Geometry.Coordinates = coords
+		return s
+	}
+	stations := []StationResponse{
+		station("KBJC", -105.1172, 39.9088),
+		station("KNONE"),
+		station("KDEN", -104.6562, 39.8466),
+		station("KAPA", -104.8492, 39.5701),
+	}
+
+	// From downtown Denver.
+	got := ByDistance(stations, 39.7392, -104.9903)
+	var ids []string
+	for _, d := range got {
+		ids = append(ids, d.Station.Properties.StationIdentifier)
+	}
+	if want := "KBJC KAPA KDEN"; strings.Join(ids, " ") != want {
+		t.Errorf("ByDistance() order = %v, want %s", ids, want)
+	}
+	if d := got[2]; math.Abs(d.Distance-30942) > 10 || d.Bearing < 60 || d.Bearing > 70 {
+		t.Errorf("KDEN distance = %.0f m, bearing %.0f", d.Distance, d.Bearing)
+	}
+}
+
+func TestMatchStations(t *testing.T) {
+	var stations []StationResponse
+	for _, s := range [][2]string{{"KDEN", "Denver International Airport"}, {"KBJC", "Rocky Mountain Metropolitan Airport"}, {"KCOS", "Colorado Springs"}} {
+		var r StationResponse
+		r.Properties.StationIdentifier, r.Properties.Name = s[0], s[1]
+		stations = append(stations, r)
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"airport", 2},
+		{"DENVER", 1},
+		{"kcos", 1},
+		{"", 3},
+		{"Boulder", 0},
+	}
+	for _, tt := range tests {
+		if got := MatchStations(stations, tt.query); len(got) != tt.want {
+			t.Errorf("MatchStations(%q) = %d stations, want %d", tt.query, len(got), tt.want)
+		}
+	}
+}
+
+func TestSortStations(t *testing.T) {
+	measured := func(id, name string, distance float64, elevation *float64) StationDistance {
+		var s StationResponse
+		s.Properties.StationIdentifier, s.Properties.Name = id, name
+		s.Properties.Elevation.Value = elevation
+		return StationDistance{Station: s, Distance: distance}
+	}
+	high, low := 2000.0, 1600.0
+	stations := []StationDistance{
+		measured("KDEN", "Denver", 30000, &low),
+		measured("KAPA", "centennial", 22000, nil),
+		measured("KBJC", "Broomfield", 21000, &high),
+	}
+
+	tests := []struct {
+		by   string
+		want string
+	}{
+		{"distance", "KBJC KAPA KDEN"},
+		{"id", "KAPA KBJC KDEN"},
+		{"name", "KBJC KAPA KDEN"},
+		{"elevation", "KBJC KDEN KAPA"},
+	}
+	for _, tt := range tests {
+		if err := SortStations(stations, tt.by); err != nil {
+			t.Fatalf("SortStations(%q) error = %v", tt.by, err)
+		}
+		var ids []string
+		for _, d := range stations {
+			ids = append(ids, d.Station.Properties.StationIdentifier)
+		}
+		if got := strings.Join(ids, " "); got != tt.want {
+			t.Errorf("SortStations(%q) = %s, want %s", tt.by, got, tt.want)
+		}
+	}
+
+	if got := WithinRadius(stations, 22000); len(got) != 2 {
+		t.Errorf("WithinRadius(22 km) = %d stations, want 2", len(got))
+	}
+	if err := SortStations(stations, "latitude"); err == nil {
+		t.Error("SortStations() expected error for unknown key")
+	}
+}
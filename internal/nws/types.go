@@ -1,33 +1,72 @@
 package nws
 
+// NullFloat64 is a quantitative value from the API. Value is nil when
+// the station didn't report it. UnitCode is a WMO or NWS unit such as
+// "wmoUnit:degC", and QualityControl is the MADIS quality control flag.
 type NullFloat64 struct {
-	Value *float64 `json:"value"`
+	Value          *float64 `json:"value"`
+	UnitCode       string   `json:"unitCode,omitempty"`
+	QualityControl string   `json:"qualityControl,omitempty"`
 }
 
+// Observation is a single station observation. When decoded from JSON,
+// values are converted from their unitCode to °C, km/h, Pa, metres
+// (elevation, visibility and cloud bases) and millimetres
+// (precipitation).
 type Observation struct {
-	Timestamp        string      `json:"timestamp"`
-	TextDescription  string      `json:"textDescription"`
-	Temperature      NullFloat64 `json:"temperature"`
-	Dewpoint         NullFloat64 `json:"dewpoint"`
-	WindDirection    NullFloat64 `json:"windDirection"`
-	WindSpeed        NullFloat64 `json:"windSpeed"`
-	WindGust         NullFloat64 `json:"windGust"`
-	Visibility       NullFloat64 `json:"visibility"`
-	RelativeHumidity NullFloat64 `json:"relativeHumidity"`
-	Barometer        NullFloat64 `json:"barometricPressure"`
-	WindChill        NullFloat64 `json:"windChill"`
+	Timestamp                 string           `json:"timestamp"`
+	TextDescription           string           `json:"textDescription"`
+	RawMessage                string           `json:"rawMessage,omitempty"`
+	Elevation                 NullFloat64      `json:"elevation"`
+	Temperature               NullFloat64      `json:"temperature"`
+	Dewpoint                  NullFloat64      `json:"dewpoint"`
+	WindDirection             NullFloat64      `json:"windDirection"`
+	WindSpeed                 NullFloat64      `json:"windSpeed"`
+	WindGust                  NullFloat64      `json:"windGust"`
+	Visibility                NullFloat64      `json:"visibility"`
+	RelativeHumidity          NullFloat64      `json:"relativeHumidity"`
+	Barometer                 NullFloat64      `json:"barometricPressure"`
+	SeaLevelPressure          NullFloat64      `json:"seaLevelPressure"`
+	WindChill                 NullFloat64      `json:"windChill"`
+	HeatIndex                 NullFloat64      `json:"heatIndex"`
+	MaxTemperatureLast24Hours NullFloat64      `json:"maxTemperatureLast24Hours"`
+	MinTemperatureLast24Hours NullFloat64      `json:"minTemperatureLast24Hours"`
+	PrecipitationLastHour     NullFloat64      `json:"precipitationLastHour"`
+	PrecipitationLast3Hours   NullFloat64      `json:"precipitationLast3Hours"`
+	PrecipitationLast6Hours   NullFloat64      `json:"precipitationLast6Hours"`
+	PresentWeather            []PresentWeather `json:"presentWeather,omitempty"`
+	CloudLayers               []CloudLayer     `json:"cloudLayers,omitempty"`
+}
+
+// PresentWeather is one weather phenomenon in an observation, such as
+// light snow or mist.
+type PresentWeather struct {
+	Intensity  string `json:"intensity,omitempty"`
+	Modifier   string `json:"modifier,omitempty"`
+	Weather    string `json:"weather"`
+	RawString  string `json:"rawString"`
+	InVicinity bool   `json:"inVicinity,omitempty"`
+}
+
+// CloudLayer is one reported cloud layer. Amount is a METAR sky cover
+// code: SKC, CLR, FEW, SCT, BKN, OVC or VV (vertical visibility).
+type CloudLayer struct {
+	Base   NullFloat64 `json:"base"`
+	Amount string      `json:"amount"`
 }
 
 type StationResponse struct {
-	Properties struct {
-		Name string `json:"name"`
-	} `json:"properties"`
+	Geometry   PointGeometry `json:"geometry"`
+	Properties Station       `json:"properties"`
 }
 
 type ObservationsResponse struct {
 	Features []struct {
 		Properties Observation `json:"properties"`
 	} `json:"features"`
+	Pagination struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
 }
 
 type ObservationResponse struct {
@@ -42,18 +81,32 @@ type PointsResponse struct {
 				State string `json:"state"`
 			} `json:"properties"`
 		} `json:"relativeLocation"`
+		CWA                 string `json:"cwa"`
+		GridID              string `json:"gridId"`
+		GridX               int    `json:"gridX"`
+		GridY               int    `json:"gridY"`
 		Forecast            string `json:"forecast"`
+		ForecastHourly      string `json:"forecastHourly"`
+		ForecastGridData    string `json:"forecastGridData"`
 		ObservationStations string `json:"observationStations"`
+		TimeZone            string `json:"timeZone"`
 	} `json:"properties"`
 }
 
+// Office returns the forecast office responsible for the point (its
+// county warning area), e.g. "BOU", falling back to the grid's office.
+func (p PointsResponse) Office() string {
+	if p.Properties.CWA != "" {
+		return p.Properties.CWA
+	}
+	return p.Properties.GridID
+}
+
 type StationsResponse struct {
-	Features []struct {
-		Properties struct {
-			StationIdentifier string `json:"stationIdentifier"`
-			Name              string `json:"name"`
-		} `json:"properties"`
-	} `json:"features"`
+	Features   []StationResponse `json:"features"`
+	Pagination struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
 }
 
 type ForecastResponse struct {
@@ -63,12 +116,18 @@ type ForecastResponse struct {
 }
 
 type ForecastPeriod struct {
-	Name             string `json:"name"`
-	Temperature      int    `json:"temperature"`
-	TemperatureUnit  string `json:"temperatureUnit"`
-	WindSpeed        string `json:"windSpeed"`
-	WindDirection    string `json:"windDirection"`
-	ShortForecast    string `json:"shortForecast"`
-	DetailedForecast string `json:"detailedForecast"`
-	IsDaytime        bool   `json:"isDaytime"`
+	Number                     int         `json:"number"`
+	Name                       string      `json:"name"`
+	StartTime                  string      `json:"startTime"`
+	EndTime                    string      `json:"endTime"`
+	Temperature                int         `json:"temperature"`
+	TemperatureUnit            string      `json:"temperatureUnit"`
+	ProbabilityOfPrecipitation NullFloat64 `json:"probabilityOfPrecipitation"`
+	Dewpoint                   NullFloat64 `json:"dewpoint"`
+	RelativeHumidity           NullFloat64 `json:"relativeHumidity"`
+	WindSpeed                  string      `json:"windSpeed"`
+	WindDirection              string      `json:"windDirection"`
+	ShortForecast              string      `json:"shortForecast"`
+	DetailedForecast           string      `json:"detailedForecast"`
+	IsDaytime                  bool        `json:"isDaytime"`
 }
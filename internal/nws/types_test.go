@@ -105,3 +105,40 @@ func TestForecastPeriod_UnmarshalJSON(t *testing.T) {
 		t.Errorf("DetailedForecast = %q", fp.DetailedForecast)
 	}
 }
+
+func TestForecastPeriod_UnmarshalJSON_Hourly(t *testing.T) {
+	input := `{
+		"number": 1,
+		"name": "",
+		"startTime": "2026-02-17T11:00:00-07:00",
+		"endTime": "2026-02-17T12:00:00-07:00",
+		"isDaytime": true,
+		"temperature": 52,
+		"temperatureUnit": "F",
+		"probabilityOfPrecipitation": {"unitCode": "wmoUnit:percent", "value": 20},
+		"dewpoint": {"unitCode": "wmoUnit:degC", "value": -5.0},
+		"relativeHumidity": {"unitCode": "wmoUnit:percent", "value": 32},
+		"windSpeed": "20 mph",
+		"windDirection": "W",
+		"shortForecast": "Partly Sunny",
+		"detailedForecast": ""
+	}`
+
+	var fp ForecastPeriod
+	if err := json.Unmarshal([]byte(input), &fp); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	if fp.StartTime != "2026-02-17T11:00:00-07:00" {
+		t.Errorf("StartTime = %q", fp.StartTime)
+	}
+	if fp.ProbabilityOfPrecipitation.Value == nil || *fp.ProbabilityOfPrecipitation.Value != 20 {
+		t.Errorf("ProbabilityOfPrecipitation unexpected")
+	}
+	if fp.Dewpoint.Value == nil || *fp.Dewpoint.Value != -5.0 {
+		t.Errorf("Dewpoint unexpected")
+	}
+	if fp.RelativeHumidity.Value == nil || *fp.RelativeHumidity.Value != 32 {
+		t.Errorf("RelativeHumidity unexpected")
+	}
+}
@@ -0,0 +1,106 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Format selects how command output is rendered.
+type Format string
+
+const (
+	FormatTable  Format = "table"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+)
+
+// Formats lists every supported output format.
+var Formats = []Format{FormatTable, FormatJSON, FormatNDJSON, FormatCSV}
+
+// ParseFormat validates a -format flag value.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (want table, json, ndjson or csv)", s)
+}
+
+// WriteJSON writes v as a single indented JSON document.
+func WriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// WriteNDJSON writes each record as one compact JSON object per line.
+func WriteNDJSON[T any](w io.Writer, records []T) error {
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes records as CSV. The header row is taken from the json
+// tags of T's fields so CSV and JSON share the same schema; nil values
+// are written as empty cells.
+func WriteCSV[T any](w io.Writer, records []T) error {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("render: WriteCSV needs a struct type, got %s", typ)
+	}
+
+	var header []string
+	var fields []int
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range records {
+		v := reflect.ValueOf(r)
+		row := make([]string, len(fields))
+		for j, i := range fields {
+			row[j] = csvValue(v.Field(i))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.String:
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
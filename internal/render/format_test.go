@@ -0,0 +1,78 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range Formats {
+		got, err := ParseFormat(string(f))
+		if err != nil || got != f {
+			t.Errorf("ParseFormat(%q) = %q, %v", f, got, err)
+		}
+	}
+	if got, err := ParseFormat("JSON"); err != nil || got != FormatJSON {
+		t.Errorf("ParseFormat(JSON) = %q, %v", got, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) expected error")
+	}
+}
+
+type testRecord struct {
+	Name    string   `json:"name"`
+	Value   *float64 `json:"value"`
+	Count   int      `json:"count"`
+	Ignored string   `json:"-"`
+	Hidden  string
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestWriteCSV(t *testing.T) {
+	records := []testRecord{
+		{Name: "a", Value: floatPtr(1.25), Count: 3, Ignored: "x", Hidden: "y"},
+		{Name: "b, c", Count: 0},
+	}
+	var b strings.Builder
+	if err := WriteCSV(&b, records); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "name,value,count\na,1.25,3\n\"b, c\",,0\n"
+	if b.String() != want {
+		t.Errorf("WriteCSV() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestWriteCSV_NotStruct(t *testing.T) {
+	var b strings.Builder
+	if err := WriteCSV(&b, []int{1}); err == nil {
+		t.Error("WriteCSV([]int) expected error")
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	records := []testRecord{{Name: "a", Value: floatPtr(1)}, {Name: "b"}}
+	var b strings.Builder
+	if err := WriteNDJSON(&b, records); err != nil {
+		t.Fatalf("WriteNDJSON() error = %v", err)
+	}
+	want := `{"name":"a","value":1,"count":0,"Hidden":""}` + "\n" +
+		`{"name":"b","value":null,"count":0,"Hidden":""}` + "\n"
+	if b.String() != want {
+		t.Errorf("WriteNDJSON() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b strings.Builder
+	if err := WriteJSON(&b, testRecord{Name: "a"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if !strings.Contains(b.String(), "\n  \"name\": \"a\",") {
+		t.Errorf("WriteJSON() not indented: %s", b.String())
+	}
+}
@@ -0,0 +1,449 @@
+package render
+
+import (
+	"math"
+	"time"
+
+	"lastwind/internal/aviation"
+	"lastwind/internal/metar"
+	"lastwind/internal/nws"
+	"lastwind/internal/stats"
+)
+
+// ObservationRecord is the machine-readable form of an observation. Each
+// quantity is given in the SI unit reported by the API and converted to
+// US units; missing values are null in JSON and empty in CSV.
+type ObservationRecord struct {
+	Timestamp        string   `json:"timestamp"`
+	Station          string   `json:"station"`
+	Description      string   `json:"description"`
+	TemperatureC     *float64 `json:"temperature_c"`
+	TemperatureF     *float64 `json:"temperature_f"`
+	DewpointC        *float64 `json:"dewpoint_c"`
+	DewpointF        *float64 `json:"dewpoint_f"`
+	RelativeHumidity *float64 `json:"relative_humidity_pct"`
+	WindDirectionDeg *float64 `json:"wind_direction_deg"`
+	WindDirection    string   `json:"wind_direction"`
+	WindSpeedKmh     *float64 `json:"wind_speed_kmh"`
+	WindSpeedMph     *float64 `json:"wind_speed_mph"`
+	WindGustKmh      *float64 `json:"wind_gust_kmh"`
+	WindGustMph      *float64 `json:"wind_gust_mph"`
+	VisibilityM      *float64 `json:"visibility_m"`
+	VisibilityMi     *float64 `json:"visibility_mi"`
+	PressurePa       *float64 `json:"pressure_pa"`
+	PressureInHg     *float64 `json:"pressure_inhg"`
+	WindChillC       *float64 `json:"wind_chill_c"`
+	WindChillF       *float64 `json:"wind_chill_f"`
+	HeatIndexC       *float64 `json:"heat_index_c"`
+	HeatIndexF       *float64 `json:"heat_index_f"`
+	SeaLevelPa       *float64 `json:"sea_level_pressure_pa"`
+	SeaLevelInHg     *float64 `json:"sea_level_pressure_inhg"`
+	Max24hC          *float64 `json:"max_temperature_24h_c"`
+	Max24hF          *float64 `json:"max_temperature_24h_f"`
+	Min24hC          *float64 `json:"min_temperature_24h_c"`
+	Min24hF          *float64 `json:"min_temperature_24h_f"`
+	Precip1hMm       *float64 `json:"precipitation_1h_mm"`
+	Precip1hIn       *float64 `json:"precipitation_1h_in"`
+	Precip3hMm       *float64 `json:"precipitation_3h_mm"`
+	Precip3hIn       *float64 `json:"precipitation_3h_in"`
+	Precip6hMm       *float64 `json:"precipitation_6h_mm"`
+	Precip6hIn       *float64 `json:"precipitation_6h_in"`
+	CeilingM         *float64 `json:"ceiling_m"`
+	CeilingFt        *float64 `json:"ceiling_ft"`
+	SkyCondition     string   `json:"sky_condition"`
+	FlightCategory   string   `json:"flight_category"`
+	PresentWeather   string   `json:"present_weather"`
+	ElevationM       *float64 `json:"elevation_m"`
+	RawMessage       string   `json:"raw_message"`
+}
+
+// NewObservationRecord converts an observation from the given station.
+// The flight category uses only the visibility qc trusts.
+func NewObservationRecord(station string, o nws.Observation, qc nws.QCPolicy) ObservationRecord {
+	ceiling := o.Ceiling()
+	return ObservationRecord{
+		Timestamp:        o.Timestamp,
+		Station:          station,
+		Description:      o.TextDescription,
+		TemperatureC:     o.Temperature.Value,
+		TemperatureF:     convert(o.Temperature.Value, nws.CToF, 1),
+		DewpointC:        o.Dewpoint.Value,
+		DewpointF:        convert(o.Dewpoint.Value, nws.CToF, 1),
+		RelativeHumidity: o.RelativeHumidity.Value,
+		WindDirectionDeg: o.WindDirection.Value,
+		WindDirection:    nws.CompassDir(o.WindDirection.Value),
+		WindSpeedKmh:     o.WindSpeed.Value,
+		WindSpeedMph:     convert(o.WindSpeed.Value, nws.KmhToMph, 1),
+		WindGustKmh:      o.WindGust.Value,
+		WindGustMph:      convert(o.WindGust.Value, nws.KmhToMph, 1),
+		VisibilityM:      o.Visibility.Value,
+		VisibilityMi:     convert(o.Visibility.Value, nws.MetersToMiles, 2),
+		PressurePa:       o.Barometer.Value,
+		PressureInHg:     convert(o.Barometer.Value, nws.PaToInHg, 2),
+		WindChillC:       o.WindChill.Value,
+		WindChillF:       convert(o.WindChill.Value, nws.CToF, 1),
+		HeatIndexC:       o.HeatIndex.Value,
+		HeatIndexF:       convert(o.HeatIndex.Value, nws.CToF, 1),
+		SeaLevelPa:       o.SeaLevelPressure.Value,
+		SeaLevelInHg:     convert(o.SeaLevelPressure.Value, nws.PaToInHg, 2),
+		Max24hC:          o.MaxTemperatureLast24Hours.Value,
+		Max24hF:          convert(o.MaxTemperatureLast24Hours.Value, nws.CToF, 1),
+		Min24hC:          o.MinTemperatureLast24Hours.Value,
+		Min24hF:          convert(o.MinTemperatureLast24Hours.Value, nws.CToF, 1),
+		Precip1hMm:       o.PrecipitationLastHour.Value,
+		Precip1hIn:       convert(o.PrecipitationLastHour.Value, mmToIn, 2),
+		Precip3hMm:       o.PrecipitationLast3Hours.Value,
+		Precip3hIn:       convert(o.PrecipitationLast3Hours.Value, mmToIn, 2),
+		Precip6hMm:       o.PrecipitationLast6Hours.Value,
+		Precip6hIn:       convert(o.PrecipitationLast6Hours.Value, mmToIn, 2),
+		CeilingM:         ceiling,
+		CeilingFt:        convert(ceiling, metersToFeet, 0),
+		SkyCondition:     o.SkyCondition(),
+		FlightCategory:   string(aviation.ObservationCategory(o, qc)),
+		PresentWeather:   o.Weather(),
+		ElevationM:       o.Elevation.Value,
+		RawMessage:       o.RawMessage,
+	}
+}
+
+// ForecastPeriodRecord is the machine-readable form of a 12-hour or
+// hourly forecast period. Temperature is as issued (see temperature_unit);
+// dewpoint and humidity are only present for hourly periods.
+type ForecastPeriodRecord struct {
+	Number              int      `json:"number"`
+	Name                string   `json:"name"`
+	StartTime           string   `json:"start_time"`
+	EndTime             string   `json:"end_time"`
+	IsDaytime           bool     `json:"is_daytime"`
+	Temperature         int      `json:"temperature"`
+	TemperatureUnit     string   `json:"temperature_unit"`
+	PrecipitationChance *float64 `json:"precipitation_probability_pct"`
+	DewpointC           *float64 `json:"dewpoint_c"`
+	DewpointF           *float64 `json:"dewpoint_f"`
+	RelativeHumidity    *float64 `json:"relative_humidity_pct"`
+	WindSpeed           string   `json:"wind_speed"`
+	WindDirection       string   `json:"wind_direction"`
+	ShortForecast       string   `json:"short_forecast"`
+	DetailedForecast    string   `json:"detailed_forecast"`
+}
+
+// NewForecastPeriodRecord converts a forecast period.
+func NewForecastPeriodRecord(p nws.ForecastPeriod) ForecastPeriodRecord {
+	return ForecastPeriodRecord{
+		Number:              p.Number,
+		Name:                p.Name,
+		StartTime:           p.StartTime,
+		EndTime:             p.EndTime,
+		IsDaytime:           p.IsDaytime,
+		Temperature:         p.Temperature,
+		TemperatureUnit:     p.TemperatureUnit,
+		PrecipitationChance: p.ProbabilityOfPrecipitation.Value,
+		DewpointC:           p.Dewpoint.Value,
+		DewpointF:           convert(p.Dewpoint.Value, nws.CToF, 1),
+		RelativeHumidity:    p.RelativeHumidity.Value,
+		WindSpeed:           p.WindSpeed,
+		WindDirection:       p.WindDirection,
+		ShortForecast:       p.ShortForecast,
+		DetailedForecast:    p.DetailedForecast,
+	}
+}
+
+// AlertRecord is the machine-readable form of an active alert.
+type AlertRecord struct {
+	Event       string   `json:"event"`
+	Severity    string   `json:"severity"`
+	Urgency     string   `json:"urgency"`
+	Certainty   string   `json:"certainty"`
+	Onset       string   `json:"onset"`
+	Ends        string   `json:"ends"`
+	Headline    string   `json:"headline"`
+	Description string   `json:"description"`
+	Instruction string   `json:"instruction"`
+	Zones       []string `json:"zones"`
+}
+
+// NewAlertRecord converts an alert.
+func NewAlertRecord(a nws.Alert) AlertRecord {
+	return AlertRecord{
+		Event:       a.Event,
+		Severity:    a.Severity,
+		Urgency:     a.Urgency,
+		Certainty:   a.Certainty,
+		Onset:       a.Onset,
+		Ends:        a.Until(),
+		Headline:    a.Headline,
+		Description: a.Description,
+		Instruction: a.Instruction,
+		Zones:       a.ZoneIDs(),
+	}
+}
+
+func mmToIn(mm float64) float64 { return mm / 25.4 }
+
+func metersToFeet(m float64) float64 { return m * 3.28084 }
+
+func convert(v *float64, fn func(float64) float64, digits int) *float64 {
+	if v == nil {
+		return nil
+	}
+	scale := math.Pow(10, float64(digits))
+	r := math.Round(fn(*v)*scale) / scale
+	return &r
+}
+
+// StationRecord identifies an observation station.
+type StationRecord struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// HistoryDocument is the JSON document produced by lastwind.
+type HistoryDocument struct {
+	Station      StationRecord       `json:"station"`
+	Observations []ObservationRecord `json:"observations"`
+	HighestWind  *ObservationRecord  `json:"highest_wind"`
+	HighestGust  *ObservationRecord  `json:"highest_gust"`
+	Statistics   StatisticsRecord    `json:"statistics"`
+}
+
+// StatisticsRecord is the machine-readable form of stats.Summary. Each
+// extreme is the full observation it came from.
+type StatisticsRecord struct {
+	Window              string             `json:"window"`
+	Observations        int                `json:"observations"`
+	CalmObservations    int                `json:"calm_observations"`
+	LowestTemperature   *ObservationRecord `json:"lowest_temperature"`
+	HighestTemperature  *ObservationRecord `json:"highest_temperature"`
+	MeanTemperatureC    *float64           `json:"mean_temperature_c"`
+	MeanTemperatureF    *float64           `json:"mean_temperature_f"`
+	LowestHumidity      *ObservationRecord `json:"lowest_humidity"`
+	HighestHumidity     *ObservationRecord `json:"highest_humidity"`
+	MeanWindSpeedKmh    *float64           `json:"mean_wind_speed_kmh"`
+	MeanWindSpeedMph    *float64           `json:"mean_wind_speed_mph"`
+	PrevailingDirection string             `json:"prevailing_wind_direction"`
+	LowestPressure      *ObservationRecord `json:"lowest_pressure"`
+	HighestPressure     *ObservationRecord `json:"highest_pressure"`
+	GustThresholdKmh    float64            `json:"gust_threshold_kmh"`
+	GustThresholdMph    float64            `json:"gust_threshold_mph"`
+	GustyHours          int                `json:"gusty_hours"`
+	FlightCategoryHours map[string]int     `json:"flight_category_hours"`
+}
+
+// NewStatisticsRecord converts a summary of a station's observations over
+// the described window, summarized with the QC policy qc.
+func NewStatisticsRecord(station, window string, s stats.Summary, qc nws.QCPolicy) StatisticsRecord {
+	extreme := func(e *stats.Extreme) *ObservationRecord {
+		if e == nil {
+			return nil
+		}
+		r := NewObservationRecord(station, e.Observation, qc)
+		return &r
+	}
+	hours := make(map[string]int)
+	for _, c := range aviation.Categories {
+		hours[string(c)] = s.CategoryHours[c]
+	}
+	return StatisticsRecord{
+		Window:              window,
+		Observations:        s.Count,
+		CalmObservations:    s.CalmCount,
+		LowestTemperature:   extreme(s.MinTemperature),
+		HighestTemperature:  extreme(s.MaxTemperature),
+		MeanTemperatureC:    convert(s.MeanTemperature, func(v float64) float64 { return v }, 1),
+		MeanTemperatureF:    convert(s.MeanTemperature, nws.CToF, 1),
+		LowestHumidity:      extreme(s.MinHumidity),
+		HighestHumidity:     extreme(s.MaxHumidity),
+		MeanWindSpeedKmh:    convert(s.MeanWindSpeed, func(v float64) float64 { return v }, 1),
+		MeanWindSpeedMph:    convert(s.MeanWindSpeed, nws.KmhToMph, 1),
+		PrevailingDirection: nws.CompassDir(s.PrevailingDirection),
+		LowestPressure:      extreme(s.MinPressure),
+		HighestPressure:     extreme(s.MaxPressure),
+		GustThresholdKmh:    s.GustThreshold,
+		GustThresholdMph:    math.Round(nws.KmhToMph(s.GustThreshold)*10) / 10,
+		GustyHours:          s.GustyHours,
+		FlightCategoryHours: hours,
+	}
+}
+
+// LocationRecord identifies a forecast point.
+type LocationRecord struct {
+	City      string  `json:"city"`
+	State     string  `json:"state"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ForecastDocument is the JSON document produced by forecast.
+type ForecastDocument struct {
+	Location LocationRecord         `json:"location"`
+	Station  StationRecord          `json:"station"`
+	Alerts   []AlertRecord          `json:"alerts"`
+	Current  ObservationRecord      `json:"current"`
+	Periods  []ForecastPeriodRecord `json:"periods"`
+	TAF      *TAFRecord             `json:"taf,omitempty"`
+}
+
+// TAFRecord is the machine-readable form of a decoded TAF. Times are
+// RFC 3339 in UTC.
+type TAFRecord struct {
+	Station   string             `json:"station"`
+	Issued    string             `json:"issued"`
+	ValidFrom string             `json:"valid_from"`
+	ValidTo   string             `json:"valid_to"`
+	Amended   bool               `json:"amended"`
+	Raw       string             `json:"raw"`
+	Segments  []TAFSegmentRecord `json:"segments"`
+}
+
+// TAFSegmentRecord is one period of a TAF. Change is empty for the
+// initial conditions, or FM, BECMG, TEMPO, PROB or "PROB TEMPO"; values
+// the segment doesn't forecast are null.
+type TAFSegmentRecord struct {
+	Change           string   `json:"change"`
+	Probability      *int     `json:"probability_pct"`
+	From             string   `json:"from"`
+	To               string   `json:"to"`
+	WindDirectionDeg *float64 `json:"wind_direction_deg"`
+	WindSpeedKmh     *float64 `json:"wind_speed_kmh"`
+	WindSpeedMph     *float64 `json:"wind_speed_mph"`
+	WindGustKmh      *float64 `json:"wind_gust_kmh"`
+	WindGustMph      *float64 `json:"wind_gust_mph"`
+	VisibilityM      *float64 `json:"visibility_m"`
+	VisibilityMi     *float64 `json:"visibility_mi"`
+	Weather          string   `json:"weather"`
+	SkyCondition     string   `json:"sky_condition"`
+	CeilingM         *float64 `json:"ceiling_m"`
+	CeilingFt        *float64 `json:"ceiling_ft"`
+	FlightCategory   string   `json:"flight_category"`
+	Raw              string   `json:"raw"`
+}
+
+// NewTAFRecord converts a decoded TAF.
+func NewTAFRecord(t metar.TAF) TAFRecord {
+	r := TAFRecord{
+		Station:   t.Station,
+		Issued:    formatTime(t.Issued),
+		ValidFrom: formatTime(t.ValidFrom),
+		ValidTo:   formatTime(t.ValidTo),
+		Amended:   t.Amended,
+		Raw:       t.Raw,
+		Segments:  []TAFSegmentRecord{},
+	}
+	for _, s := range t.Segments {
+		r.Segments = append(r.Segments, NewTAFSegmentRecord(s))
+	}
+	return r
+}
+
+// NewTAFSegmentRecord converts a TAF segment.
+func NewTAFSegmentRecord(s metar.Segment) TAFSegmentRecord {
+	ceiling := s.Ceiling()
+	r := TAFSegmentRecord{
+		Change:         s.Change,
+		From:           formatTime(s.From),
+		To:             formatTime(s.To),
+		Weather:        s.WeatherCodes(),
+		SkyCondition:   s.SkyCondition(),
+		CeilingM:       ceiling,
+		CeilingFt:      convert(ceiling, metersToFeet, 0),
+		FlightCategory: string(aviation.SegmentCategory(s)),
+		Raw:            s.Raw,
+	}
+	if s.Probability > 0 {
+		r.Probability = &s.Probability
+	}
+	if w := s.Wind; w != nil {
+		r.WindDirectionDeg = w.Direction
+		r.WindSpeedKmh = convert(&w.Speed, func(v float64) float64 { return v }, 1)
+		r.WindSpeedMph = convert(&w.Speed, nws.KmhToMph, 1)
+		r.WindGustKmh = convert(w.Gust, func(v float64) float64 { return v }, 1)
+		r.WindGustMph = convert(w.Gust, nws.KmhToMph, 1)
+	}
+	if v := s.Visibility; v != nil {
+		r.VisibilityM = convert(&v.Distance, func(v float64) float64 { return v }, 0)
+		r.VisibilityMi = convert(&v.Distance, nws.MetersToMiles, 2)
+	}
+	return r
+}
+
+// formatTime formats t as RFC 3339, or returns "" for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// StationInfoRecord is the machine-readable form of a station's metadata.
+type StationInfoRecord struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Latitude        *float64 `json:"latitude"`
+	Longitude       *float64 `json:"longitude"`
+	ElevationM      *float64 `json:"elevation_m"`
+	ElevationFt     *float64 `json:"elevation_ft"`
+	TimeZone        string   `json:"time_zone"`
+	Office          string   `json:"office"`
+	ForecastZone    string   `json:"forecast_zone"`
+	County          string   `json:"county"`
+	FireWeatherZone string   `json:"fire_weather_zone"`
+}
+
+// NewStationInfoRecord converts a station's metadata. office is its
+// forecast office, which the station endpoint doesn't report.
+func NewStationInfoRecord(s nws.StationResponse, office string) StationInfoRecord {
+	r := StationInfoRecord{
+		ID:              s.Properties.StationIdentifier,
+		Name:            s.Properties.Name,
+		ElevationM:      s.Properties.Elevation.Value,
+		ElevationFt:     convert(s.Properties.Elevation.Value, metersToFeet, 0),
+		TimeZone:        s.Properties.TimeZone,
+		Office:          office,
+		ForecastZone:    nws.ZoneID(s.Properties.Forecast),
+		County:          nws.ZoneID(s.Properties.County),
+		FireWeatherZone: nws.ZoneID(s.Properties.FireWeatherZone),
+	}
+	if lat, lon, ok := s.Geometry.LatLon(); ok {
+		r.Latitude, r.Longitude = &lat, &lon
+	}
+	return r
+}
+
+// NearbyStationRecord is a station with its distance and bearing from the
+// configured location.
+type NearbyStationRecord struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	ElevationM  *float64 `json:"elevation_m"`
+	ElevationFt *float64 `json:"elevation_ft"`
+	DistanceKm  float64  `json:"distance_km"`
+	DistanceMi  float64  `json:"distance_mi"`
+	BearingDeg  float64  `json:"bearing_deg"`
+	Bearing     string   `json:"bearing"`
+}
+
+// NewNearbyStationRecord converts a measured station.
+func NewNearbyStationRecord(d nws.StationDistance) NearbyStationRecord {
+	bearing := math.Mod(math.Round(d.Bearing), 360)
+	return NearbyStationRecord{
+		ID:          d.Station.Properties.StationIdentifier,
+		Name:        d.Station.Properties.Name,
+		ElevationM:  d.Station.Properties.Elevation.Value,
+		ElevationFt: convert(d.Station.Properties.Elevation.Value, metersToFeet, 0),
+		DistanceKm:  math.Round(d.Distance/100) / 10,
+		DistanceMi:  math.Round(nws.MetersToMiles(d.Distance)*10) / 10,
+		BearingDeg:  bearing,
+		Bearing:     nws.CompassDir(&bearing),
+	}
+}
+
+// StationDocument is the JSON document produced by station.
+type StationDocument struct {
+	Station StationInfoRecord     `json:"station"`
+	Nearby  []NearbyStationRecord `json:"nearby"`
+}
+
+// StationSearchDocument is the JSON document produced by a station search.
+type StationSearchDocument struct {
+	Stations []NearbyStationRecord `json:"stations"`
+}
@@ -0,0 +1,228 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"lastwind/internal/aviation"
+	"lastwind/internal/metar"
+	"lastwind/internal/nws"
+	"lastwind/internal/stats"
+)
+
+func TestNewObservationRecord(t *testing.T) {
+	o := nws.Observation{
+		Timestamp:       "2026-02-17T17:53:00+00:00",
+		TextDescription: "Partly Cloudy",
+		Temperature:     nws.NullFloat64{Value: floatPtr(11.1)},
+		WindDirection:   nws.NullFloat64{Value: floatPtr(270)},
+		WindSpeed:       nws.NullFloat64{Value: floatPtr(32.4)},
+		Visibility:      nws.NullFloat64{Value: floatPtr(16090)},
+		Barometer:       nws.NullFloat64{Value: floatPtr(99870)},
+	}
+	r := NewObservationRecord("KDEN", o, nws.QCLenient)
+
+	if r.Station != "KDEN" || r.Timestamp != o.Timestamp || r.Description != "Partly Cloudy" {
+		t.Errorf("unexpected identity fields %+v", r)
+	}
+	if r.TemperatureC == nil || *r.TemperatureC != 11.1 {
+		t.Errorf("TemperatureC unexpected")
+	}
+	if r.TemperatureF == nil || *r.TemperatureF != 52 {
+		t.Errorf("TemperatureF = %v, want 52", r.TemperatureF)
+	}
+	if r.WindDirection != "W" {
+		t.Errorf("WindDirection = %q, want W", r.WindDirection)
+	}
+	if r.WindSpeedMph == nil || *r.WindSpeedMph != 20.1 {
+		t.Errorf("WindSpeedMph = %v, want 20.1", r.WindSpeedMph)
+	}
+	if r.VisibilityMi == nil || *r.VisibilityMi != 10 {
+		t.Errorf("VisibilityMi = %v, want 10", r.VisibilityMi)
+	}
+	if r.PressureInHg == nil || *r.PressureInHg != 29.49 {
+		t.Errorf("PressureInHg = %v, want 29.49", r.PressureInHg)
+	}
+	if r.DewpointC != nil || r.DewpointF != nil || r.WindGustMph != nil {
+		t.Errorf("missing values should be nil")
+	}
+}
+
+func TestNewObservationRecord_CloudsAndPrecipitation(t *testing.T) {
+	o := nws.Observation{
+		RawMessage:            "KDEN 171815Z 27017KT 3SM -SN BKN040 M02/M05 A2992",
+		Visibility:            nws.NullFloat64{Value: floatPtr(4830)},
+		PrecipitationLastHour: nws.NullFloat64{Value: floatPtr(2.54)},
+		PresentWeather:        []nws.PresentWeather{{Weather: "snow", RawString: "-SN"}},
+		CloudLayers: []nws.CloudLayer{
+			{Amount: "BKN", Base: nws.NullFloat64{Value: floatPtr(1220)}},
+		},
+	}
+	r := NewObservationRecord("KDEN", o, nws.QCLenient)
+	if r.Precip1hIn == nil || *r.Precip1hIn != 0.1 {
+		t.Errorf("Precip1hIn = %v, want 0.1", r.Precip1hIn)
+	}
+	if r.CeilingFt == nil || *r.CeilingFt != 4003 {
+		t.Errorf("CeilingFt = %v, want 4003", r.CeilingFt)
+	}
+	if r.SkyCondition != "BKN040" || r.PresentWeather != "-SN" || r.RawMessage != o.RawMessage {
+		t.Errorf("unexpected sky %q, weather %q or raw message %q", r.SkyCondition, r.PresentWeather, r.RawMessage)
+	}
+	if r.FlightCategory != "MVFR" {
+		t.Errorf("FlightCategory = %q, want MVFR", r.FlightCategory)
+	}
+	if r.Precip3hMm != nil || r.HeatIndexC != nil {
+		t.Errorf("missing values should be nil")
+	}
+}
+
+func TestNewObservationRecord_QC(t *testing.T) {
+	o := nws.Observation{
+		Visibility:  nws.NullFloat64{Value: floatPtr(800), QualityControl: "X"},
+		CloudLayers: []nws.CloudLayer{{Amount: "OVC", Base: nws.NullFloat64{Value: floatPtr(3000)}}},
+	}
+	tests := []struct {
+		qc   nws.QCPolicy
+		want string
+	}{
+		{nws.QCOff, "LIFR"},
+		{nws.QCLenient, ""},
+	}
+	for _, tt := range tests {
+		if got := NewObservationRecord("KDEN", o, tt.qc).FlightCategory; got != tt.want {
+			t.Errorf("FlightCategory with %s = %q, want %q", tt.qc, got, tt.want)
+		}
+	}
+}
+
+func TestNewStatisticsRecord(t *testing.T) {
+	hot := nws.Observation{Timestamp: "2026-02-17T12:53:00Z", Temperature: nws.NullFloat64{Value: floatPtr(30)}}
+	s := stats.Summary{
+		Count:               2,
+		CalmCount:           1,
+		MaxTemperature:      &stats.Extreme{Value: 30, Observation: hot},
+		MeanTemperature:     floatPtr(20.04),
+		PrevailingDirection: floatPtr(270),
+		GustThreshold:       40,
+		GustyHours:          3,
+		CategoryHours:       map[aviation.FlightCategory]int{aviation.VFR: 40, aviation.IFR: 2},
+	}
+	r := NewStatisticsRecord("KDEN", "3 days", s, nws.QCLenient)
+
+	if r.Window != "3 days" || r.Observations != 2 || r.CalmObservations != 1 || r.GustyHours != 3 {
+		t.Errorf("unexpected counts %+v", r)
+	}
+	if r.HighestTemperature == nil || r.HighestTemperature.Timestamp != hot.Timestamp || r.HighestTemperature.Station != "KDEN" {
+		t.Errorf("HighestTemperature = %+v, want the hot observation", r.HighestTemperature)
+	}
+	if r.LowestTemperature != nil || r.MeanWindSpeedKmh != nil {
+		t.Errorf("missing statistics should be nil")
+	}
+	if r.MeanTemperatureC == nil || *r.MeanTemperatureC != 20 || *r.MeanTemperatureF != 68.1 {
+		t.Errorf("MeanTemperature = %v / %v, want 20 / 68.1", r.MeanTemperatureC, r.MeanTemperatureF)
+	}
+	if r.PrevailingDirection != "W" || r.GustThresholdMph != 24.9 {
+		t.Errorf("PrevailingDirection = %q, GustThresholdMph = %v", r.PrevailingDirection, r.GustThresholdMph)
+	}
+	if h := r.FlightCategoryHours; len(h) != 4 || h["VFR"] != 40 || h["IFR"] != 2 || h["LIFR"] != 0 {
+		t.Errorf("FlightCategoryHours = %v", h)
+	}
+}
+
+func TestNewForecastPeriodRecord(t *testing.T) {
+	p := nws.ForecastPeriod{
+		Number:        1,
+		Name:          "Today",
+		Temperature:   55,
+		Dewpoint:      nws.NullFloat64{Value: floatPtr(-5)},
+		WindSpeed:     "16 to 25 mph",
+		WindDirection: "SW",
+	}
+	r := NewForecastPeriodRecord(p)
+	if r.Number != 1 || r.Name != "Today" || r.Temperature != 55 || r.WindSpeed != "16 to 25 mph" {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if r.DewpointF == nil || *r.DewpointF != 23 {
+		t.Errorf("DewpointF = %v, want 23", r.DewpointF)
+	}
+	if r.PrecipitationChance != nil {
+		t.Errorf("PrecipitationChance should be nil")
+	}
+}
+
+func TestNewTAFRecord(t *testing.T) {
+	taf, err := metar.ParseTAF("TAF AMD KDEN 171720Z 1718/1824 27015G25KT P6SM BKN025 PROB30 1720/1724 VRB20G35KT 2SM TSRA", time.Date(2024, 3, 17, 17, 20, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewTAFRecord(taf)
+	if r.Station != "KDEN" || !r.Amended || r.ValidFrom != "2024-03-17T18:00:00Z" || r.ValidTo != "2024-03-19T00:00:00Z" || len(r.Segments) != 2 {
+		t.Fatalf("unexpected record %+v", r)
+	}
+	initial := r.Segments[0]
+	if initial.WindSpeedMph == nil || *initial.WindSpeedMph != 17.3 || initial.CeilingFt == nil || *initial.CeilingFt != 2500 || initial.FlightCategory != "MVFR" {
+		t.Errorf("initial = %+v", initial)
+	}
+	if initial.Probability != nil || initial.To != "2024-03-19T00:00:00Z" {
+		t.Errorf("initial probability %v, to %q", initial.Probability, initial.To)
+	}
+	prob := r.Segments[1]
+	if prob.Change != "PROB" || prob.Probability == nil || *prob.Probability != 30 || prob.WindDirectionDeg != nil || prob.Weather != "TSRA" || prob.FlightCategory != "IFR" {
+		t.Errorf("prob = %+v", prob)
+	}
+}
+
+func TestNewAlertRecord(t *testing.T) {
+	a := nws.Alert{
+		Event:         "Wind Advisory",
+		Expires:       "2026-02-17T18:00:00-07:00",
+		AffectedZones: []string{"https://api.weather.gov/zones/forecast/COZ039"},
+	}
+	r := NewAlertRecord(a)
+	if r.Event != "Wind Advisory" || r.Ends != a.Expires {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if len(r.Zones) != 1 || r.Zones[0] != "COZ039" {
+		t.Errorf("Zones = %v, want [COZ039]", r.Zones)
+	}
+}
+
+func TestNewStationInfoRecord(t *testing.T) {
+	var s nws.StationResponse
+	s.Geometry.Coordinates = []float64{-104.6562, 39.8466}
+	s.Properties = nws.Station{
+		StationIdentifier: "KDEN",
+		Name:              "Denver International Airport",
+		TimeZone:          "America/Denver",
+		Elevation:         nws.NullFloat64{Value: floatPtr(1647.1)},
+		Forecast:          "https://api.weather.gov/zones/forecast/COZ039",
+		County:            "https://api.weather.gov/zones/county/COC001",
+	}
+	r := NewStationInfoRecord(s, "BOU")
+
+	if r.ID != "KDEN" || r.Office != "BOU" || r.ForecastZone != "COZ039" || r.County != "COC001" {
+		t.Errorf("NewStationInfoRecord() = %+v", r)
+	}
+	if r.FireWeatherZone != "" {
+		t.Errorf("FireWeatherZone = %q, want empty when not reported", r.FireWeatherZone)
+	}
+	if r.Latitude == nil || *r.Latitude != 39.8466 || *r.Longitude != -104.6562 {
+		t.Errorf("coordinates = %v, %v", r.Latitude, r.Longitude)
+	}
+	if r.ElevationFt == nil || *r.ElevationFt != 5404 {
+		t.Errorf("ElevationFt = %v, want 5404", r.ElevationFt)
+	}
+}
+
+func TestNewNearbyStationRecord(t *testing.T) {
+	var s nws.StationResponse
+	s.Properties.StationIdentifier = "KBJC"
+	r := NewNearbyStationRecord(nws.StationDistance{Station: s, Distance: 21751, Bearing: 330.2})
+
+	if r.ID != "KBJC" || r.DistanceKm != 21.8 || r.DistanceMi != 13.5 {
+		t.Errorf("NewNearbyStationRecord() = %+v", r)
+	}
+	if r.BearingDeg != 330 || r.Bearing != "NNW" {
+		t.Errorf("bearing = %v %s, want 330 NNW", r.BearingDeg, r.Bearing)
+	}
+}
@@ -0,0 +1,126 @@
+package render
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Column describes one column of a box-drawn table. A zero Width sizes
+// the column to fit its widest cell.
+type Column struct {
+	Header string
+	Width  int
+	Right  bool
+}
+
+// Table is a box-drawn table such as the lastwind observation history.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+}
+
+// AddRow appends a row of cells, one per column.
+func (t *Table) AddRow(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Write draws the table with box-drawing characters, prefixing every line
+// with indent. Cells wider than a fixed-width column are truncated.
+func (t Table) Write(w io.Writer, indent string) error {
+	widths := make([]int, len(t.Columns))
+	for i, c := range t.Columns {
+		widths[i] = c.Width
+		if widths[i] > 0 {
+			continue
+		}
+		widths[i] = VisibleWidth(c.Header)
+		for _, row := range t.Rows {
+			if i < len(row) && VisibleWidth(row[i]) > widths[i] {
+				widths[i] = VisibleWidth(row[i])
+			}
+		}
+	}
+
+	rule := func(left, mid, right string) string {
+		parts := make([]string, len(widths))
+		for i, wd := range widths {
+			parts[i] = strings.Repeat("─", wd+2)
+		}
+		return indent + left + strings.Join(parts, mid) + right + "\n"
+	}
+	line := func(cells []string, header bool) string {
+		parts := make([]string, len(widths))
+		for i, wd := range widths {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			parts[i] = " " + pad(fit(cell, wd), wd, t.Columns[i].Right && !header) + " "
+		}
+		return indent + "│" + strings.Join(parts, "│") + "│\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(rule("┌", "┬", "┐"))
+	headers := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		headers[i] = c.Header
+	}
+	b.WriteString(line(headers, true))
+	b.WriteString(rule("├", "┼", "┤"))
+	for _, row := range t.Rows {
+		b.WriteString(line(row, false))
+	}
+	b.WriteString(rule("└", "┴", "┘"))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// VisibleWidth returns the number of terminal columns s occupies,
+// ignoring ANSI colour sequences.
+func VisibleWidth(s string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(s, ""))
+}
+
+// fit truncates s to width visible runes, ending it with an ellipsis. It
+// keeps every ANSI sequence, so a colour is still reset after the cut.
+func fit(s string, width int) string {
+	if VisibleWidth(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	visible := 0
+	for len(s) > 0 {
+		if loc := ansiPattern.FindStringIndex(s); loc != nil && loc[0] == 0 {
+			b.WriteString(s[:loc[1]])
+			s = s[loc[1]:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		switch {
+		case visible < width-1:
+			b.WriteRune(r)
+		case visible == width-1:
+			b.WriteString("…")
+		}
+		visible++
+	}
+	return b.String()
+}
+
+func pad(s string, width int, right bool) string {
+	n := width - VisibleWidth(s)
+	if n <= 0 {
+		return s
+	}
+	if right {
+		return strings.Repeat(" ", n) + s
+	}
+	return s + strings.Repeat(" ", n)
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTable_Write(t *testing.T) {
+	table := Table{Columns: []Column{
+		{Header: "Time", Width: 6},
+		{Header: "Val", Width: 5, Right: true},
+		{Header: "Auto"},
+	}}
+	table.AddRow("10:00", "1.5", "x")
+	table.AddRow("11:00", "12.0", "longer")
+
+	var b strings.Builder
+	if err := table.Write(&b, "  "); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := "" +
+		"  ┌────────┬───────┬────────┐\n" +
+		"  │ Time   │ Val   │ Auto   │\n" +
+		"  ├────────┼───────┼────────┤\n" +
+		"  │ 10:00  │   1.5 │ x      │\n" +
+		"  │ 11:00  │  12.0 │ longer │\n" +
+		"  └────────┴───────┴────────┘\n"
+	if b.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestTable_WriteTruncates(t *testing.T) {
+	table := Table{Columns: []Column{{Header: "Weather", Width: 8}}}
+	table.AddRow("Mostly Cloudy and Windy")
+
+	var b strings.Builder
+	table.Write(&b, "")
+	if !strings.Contains(b.String(), "│ Mostly … │") {
+		t.Errorf("Write() did not truncate:\n%s", b.String())
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"Windy", 8, "Windy"},
+		{"Mostly Cloudy", 8, "Mostly …"},
+		{"\033[31mMostly Cloudy\033[0m", 8, "\033[31mMostly …\033[0m"},
+		{"\033[1m\033[31mVFR\033[0m", 3, "\033[1m\033[31mVFR\033[0m"},
+		{"°F and more", 4, "°F …"},
+	}
+	for _, tt := range tests {
+		if got := fit(tt.input, tt.width); got != tt.want {
+			t.Errorf("fit(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"°F…", 3},
+		{"\033[1m\033[31mVFR\033[0m", 3},
+	}
+	for _, tt := range tests {
+		if got := VisibleWidth(tt.input); got != tt.want {
+			t.Errorf("VisibleWidth(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Package stats summarises a series of observations.
+package stats
+
+import (
+	"math"
+	"time"
+
+	"lastwind/internal/aviation"
+	"lastwind/internal/nws"
+)
+
+// DefaultGustThreshold is the gust speed in km/h (about 25 mph) counted
+// towards GustyHours when Options doesn't give one.
+const DefaultGustThreshold = 40.0
+
+// Options controls how observations are summarised.
+type Options struct {
+	// QC excludes values that failed quality control.
+	QC nws.QCPolicy
+	// GustThreshold is the gust speed in km/h at or above which an hour
+	// counts as gusty.
+	GustThreshold float64
+}
+
+// Extreme is the highest or lowest value of a quantity and the
+// observation it came from.
+type Extreme struct {
+	Value       float64
+	Observation nws.Observation
+}
+
+// Summary holds statistics for a set of observations. Extremes and means
+// are nil when no observation reported the quantity. Temperatures are in
+// °C, speeds in km/h and pressures in Pa.
+type Summary struct {
+	Count int
+
+	MinTemperature, MaxTemperature *Extreme
+	MeanTemperature                *float64
+
+	MinHumidity, MaxHumidity *Extreme
+
+	MaxWind, MaxGust    *Extreme
+	MeanWindSpeed       *float64
+	PrevailingDirection *float64
+
+	MinPressure, MaxPressure *Extreme
+
+	GustThreshold float64
+	GustyHours    int
+	CalmCount     int
+
+	// CategoryHours counts the clock hours spent in each flight category,
+	// taking the worst category observed during an hour.
+	CategoryHours map[aviation.FlightCategory]int
+}
+
+// Summarize computes statistics for observations, skipping values
+// excluded by the quality control policy.
+func Summarize(observations []nws.Observation, opts Options) Summary {
+	s := Summary{Count: len(observations), GustThreshold: opts.GustThreshold}
+	if s.GustThreshold <= 0 {
+		s.GustThreshold = DefaultGustThreshold
+	}
+
+	var temp, wind mean
+	var sectors [16]int
+	gustyHours := make(map[time.Time]bool)
+	categoryHours := make(map[time.Time]aviation.FlightCategory)
+
+	for _, o := range observations {
+		if v := opts.QC.Value(o.Temperature); v != nil {
+			temp.add(*v)
+			s.MinTemperature = lower(s.MinTemperature, *v, o)
+			s.MaxTemperature = higher(s.MaxTemperature, *v, o)
+		}
+		if v := opts.QC.Value(o.RelativeHumidity); v != nil {
+			s.MinHumidity = lower(s.MinHumidity, *v, o)
+			s.MaxHumidity = higher(s.MaxHumidity, *v, o)
+		}
+		if v := opts.QC.Value(o.Barometer); v != nil {
+			s.MinPressure = lower(s.MinPressure, *v, o)
+			s.MaxPressure = higher(s.MaxPressure, *v, o)
+		}
+
+		if v := opts.QC.Value(o.WindSpeed); v != nil {
+			wind.add(*v)
+			if *v == 0 {
+				s.CalmCount++
+			} else {
+				s.MaxWind = higher(s.MaxWind, *v, o)
+				if d := opts.QC.Value(o.WindDirection); d != nil {
+					sectors[int(math.Round(*d/22.5))%16]++
+				}
+			}
+		}
+		if v := opts.QC.Value(o.WindGust); v != nil && *v > 0 {
+			s.MaxGust = higher(s.MaxGust, *v, o)
+			if *v >= s.GustThreshold {
+				if t, err := time.Parse(time.RFC3339, o.Timestamp); err == nil {
+					gustyHours[t.UTC().Truncate(time.Hour)] = true
+				}
+			}
+		}
+		if c := aviation.ObservationCategory(o, opts.QC); c != aviation.Unknown {
+			if t, err := time.Parse(time.RFC3339, o.Timestamp); err == nil {
+				hour := t.UTC().Truncate(time.Hour)
+				if c.Rank() > categoryHours[hour].Rank() {
+					categoryHours[hour] = c
+				}
+			}
+		}
+	}
+
+	s.MeanTemperature = temp.value()
+	s.MeanWindSpeed = wind.value()
+	s.GustyHours = len(gustyHours)
+	s.CategoryHours = make(map[aviation.FlightCategory]int)
+	for _, c := range categoryHours {
+		s.CategoryHours[c]++
+	}
+
+	best := -1
+	for i, n := range sectors {
+		if n > 0 && (best < 0 || n > sectors[best]) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		d := float64(best) * 22.5
+		s.PrevailingDirection = &d
+	}
+	return s
+}
+
+type mean struct {
+	sum float64
+	n   int
+}
+
+func (m *mean) add(v float64) {
+	m.sum += v
+	m.n++
+}
+
+func (m mean) value() *float64 {
+	if m.n == 0 {
+		return nil
+	}
+	v := m.sum / float64(m.n)
+	return &v
+}
+
+// higher returns the extreme with the larger value, keeping the earlier
+// one (observations are newest first, so the most recent) on ties.
+func higher(e *Extreme, v float64, o nws.Observation) *Extreme {
+	if e == nil || v > e.Value {
+		return &Extreme{Value: v, Observation: o}
+	}
+	return e
+}
+
+func lower(e *Extreme, v float64, o nws.Observation) *Extreme {
+	if e == nil || v < e.Value {
+		return &Extreme{Value: v, Observation: o}
+	}
+	return e
+}
@@ -0,0 +1,123 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"lastwind/internal/aviation"
+	"lastwind/internal/nws"
+)
+
+func value(v float64) nws.NullFloat64 {
+	return nws.NullFloat64{Value: &v}
+}
+
+func flagged(v float64, qc string) nws.NullFloat64 {
+	return nws.NullFloat64{Value: &v, QualityControl: qc}
+}
+
+// Newest first, as returned by the API and the archive.
+var observations = []nws.Observation{
+	{Timestamp: "2026-02-17T12:53:00Z", Temperature: value(10), RelativeHumidity: value(30), Barometer: value(100500),
+		WindSpeed: value(20), WindDirection: value(270), WindGust: value(45)},
+	{Timestamp: "2026-02-17T12:15:00Z", Temperature: value(9), RelativeHumidity: value(35), Barometer: value(100600),
+		WindSpeed: value(30), WindDirection: value(280), WindGust: value(55)},
+	{Timestamp: "2026-02-17T11:53:00Z", Temperature: value(6), RelativeHumidity: value(50), Barometer: value(100900),
+		WindSpeed: value(10), WindDirection: value(180), WindGust: flagged(160, "X")},
+	{Timestamp: "2026-02-17T10:53:00Z", Temperature: value(2), RelativeHumidity: value(80), Barometer: value(101200),
+		WindSpeed: value(0)},
+	{Timestamp: "2026-02-17T09:53:00Z", Temperature: flagged(-40, "Q"), RelativeHumidity: value(85),
+		WindSpeed: value(0)},
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize(observations, Options{QC: nws.QCStrict})
+
+	if s.Count != 5 {
+		t.Errorf("Count = %d, want 5", s.Count)
+	}
+	extremes := []struct {
+		name string
+		e    *Extreme
+		want float64
+		at   string
+	}{
+		{"MinTemperature", s.MinTemperature, 2, "2026-02-17T10:53:00Z"},
+		{"MaxTemperature", s.MaxTemperature, 10, "2026-02-17T12:53:00Z"},
+		{"MinHumidity", s.MinHumidity, 30, "2026-02-17T12:53:00Z"},
+		{"MaxHumidity", s.MaxHumidity, 85, "2026-02-17T09:53:00Z"},
+		{"MaxWind", s.MaxWind, 30, "2026-02-17T12:15:00Z"},
+		{"MaxGust", s.MaxGust, 55, "2026-02-17T12:15:00Z"},
+		{"MinPressure", s.MinPressure, 100500, "2026-02-17T12:53:00Z"},
+		{"MaxPressure", s.MaxPressure, 101200, "2026-02-17T10:53:00Z"},
+	}
+	for _, tt := range extremes {
+		if tt.e == nil {
+			t.Errorf("%s is nil", tt.name)
+			continue
+		}
+		if tt.e.Value != tt.want || tt.e.Observation.Timestamp != tt.at {
+			t.Errorf("%s = %v at %s, want %v at %s", tt.name, tt.e.Value, tt.e.Observation.Timestamp, tt.want, tt.at)
+		}
+	}
+
+	if s.MeanTemperature == nil || math.Abs(*s.MeanTemperature-6.75) > 1e-9 {
+		t.Errorf("MeanTemperature = %v, want 6.75", s.MeanTemperature)
+	}
+	if s.MeanWindSpeed == nil || *s.MeanWindSpeed != 12 {
+		t.Errorf("MeanWindSpeed = %v, want 12", s.MeanWindSpeed)
+	}
+	if s.PrevailingDirection == nil || *s.PrevailingDirection != 270 {
+		t.Errorf("PrevailingDirection = %v, want 270", s.PrevailingDirection)
+	}
+	if s.CalmCount != 2 {
+		t.Errorf("CalmCount = %d, want 2", s.CalmCount)
+	}
+	// 45 and 55 km/h gusts both fall in the 12:00 hour.
+	if s.GustThreshold != DefaultGustThreshold || s.GustyHours != 1 {
+		t.Errorf("GustyHours = %d over %v, want 1 over %v", s.GustyHours, s.GustThreshold, DefaultGustThreshold)
+	}
+}
+
+func TestSummarize_QCOff(t *testing.T) {
+	s := Summarize(observations, Options{QC: nws.QCOff, GustThreshold: 50})
+	if s.MaxGust.Value != 160 {
+		t.Errorf("MaxGust = %v, want rejected 160 with QC off", s.MaxGust.Value)
+	}
+	if s.MinTemperature.Value != -40 {
+		t.Errorf("MinTemperature = %v, want questioned -40 with QC off", s.MinTemperature.Value)
+	}
+	if s.GustyHours != 2 {
+		t.Errorf("GustyHours = %d, want 2", s.GustyHours)
+	}
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	s := Summarize(nil, Options{})
+	if s.Count != 0 || s.MaxWind != nil || s.MeanTemperature != nil || s.PrevailingDirection != nil {
+		t.Errorf("Summarize(nil) = %+v, want empty summary", s)
+	}
+}
+
+func TestSummarize_CategoryHours(t *testing.T) {
+	layer := func(amount string, m float64) []nws.CloudLayer {
+		return []nws.CloudLayer{{Amount: amount, Base: value(m)}}
+	}
+	obs := []nws.Observation{
+		{Timestamp: "2026-02-17T13:53:00Z", Visibility: value(16090)},
+		{Timestamp: "2026-02-17T13:15:00Z", Visibility: value(4830), CloudLayers: layer("OVC", 120)},
+		{Timestamp: "2026-02-17T12:53:00Z", Visibility: value(16090), CloudLayers: layer("BKN", 610)},
+		{Timestamp: "2026-02-17T11:53:00Z", Visibility: value(16090), CloudLayers: layer("SCT", 240)},
+		{Timestamp: "2026-02-17T10:53:00Z"},
+	}
+	s := Summarize(obs, Options{})
+	want := map[aviation.FlightCategory]int{aviation.VFR: 1, aviation.MVFR: 1, aviation.LIFR: 1}
+	if len(s.CategoryHours) != len(want) {
+		t.Fatalf("CategoryHours = %v, want %v", s.CategoryHours, want)
+	}
+	for c, n := range want {
+		if s.CategoryHours[c] != n {
+			t.Errorf("CategoryHours[%s] = %d, want %d", c, s.CategoryHours[c], n)
+		}
+	}
+}
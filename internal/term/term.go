@@ -0,0 +1,38 @@
+package term
+
+import "os"
+
+// ANSI colour codes used to highlight output.
+const (
+	Reset  = "\033[0m"
+	Bold   = "\033[1m"
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+	Blue   = "\033[34m"
+	Purple = "\033[35m"
+	Cyan   = "\033[36m"
+)
+
+// Enabled reports whether stdout is a terminal and NO_COLOR is unset.
+var Enabled = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Color wraps s in the given ANSI codes when colour output is enabled.
+func Color(s string, codes ...string) string {
+	if !Enabled || len(codes) == 0 {
+		return s
+	}
+	prefix := ""
+	for _, c := range codes {
+		prefix += c
+	}
+	return prefix + s + Reset
+}
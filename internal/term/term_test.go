@@ -0,0 +1,21 @@
+package term
+
+import "testing"
+
+func TestColor(t *testing.T) {
+	orig := Enabled
+	defer func() { Enabled = orig }()
+
+	Enabled = false
+	if got := Color("hi", Red); got != "hi" {
+		t.Errorf("Color() disabled = %q, want %q", got, "hi")
+	}
+
+	Enabled = true
+	if got := Color("hi", Bold, Red); got != Bold+Red+"hi"+Reset {
+		t.Errorf("Color() enabled = %q", got)
+	}
+	if got := Color("hi"); got != "hi" {
+		t.Errorf("Color() with no codes = %q, want %q", got, "hi")
+	}
+}
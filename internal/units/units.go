@@ -0,0 +1,318 @@
+// Package units converts the SI values reported by the NWS API into the
+// unit system chosen for display.
+package units
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Temperature is a display unit for temperatures, converted from °C.
+type Temperature string
+
+// Speed is a display unit for wind speeds, converted from km/h.
+type Speed string
+
+// Distance is a display unit for visibility, converted from metres. It
+// also decides whether heights and precipitation are shown in feet and
+// inches or metres and millimetres.
+type Distance string
+
+// Pressure is a display unit for barometric pressure, converted from Pa.
+type Pressure string
+
+const (
+	Fahrenheit Temperature = "F"
+	Celsius    Temperature = "C"
+
+	MPH   Speed = "mph"
+	KMH   Speed = "kmh"
+	Knots Speed = "kt"
+	MPS   Speed = "ms"
+
+	Miles      Distance = "mi"
+	Kilometers Distance = "km"
+
+	InHg Pressure = "inHg"
+	HPa  Pressure = "hPa"
+)
+
+// System is the set of units used to display each quantity.
+type System struct {
+	Temperature Temperature
+	Speed       Speed
+	Distance    Distance
+	Pressure    Pressure
+}
+
+// The named unit systems.
+var (
+	US       = System{Fahrenheit, MPH, Miles, InHg}
+	SI       = System{Celsius, MPS, Kilometers, HPa}
+	Metric   = System{Celsius, KMH, Kilometers, HPa}
+	Aviation = System{Celsius, Knots, Miles, InHg}
+)
+
+var systems = map[string]System{
+	"us":       US,
+	"imperial": US,
+	"si":       SI,
+	"metric":   Metric,
+	"aviation": Aviation,
+}
+
+// Names lists the accepted system names.
+var Names = []string{"us", "si", "metric", "aviation"}
+
+// Parse parses a unit system name, optionally followed by comma-separated
+// quantity=unit overrides, such as "metric" or "aviation,temperature=F".
+// An empty spec is the US system.
+func Parse(spec string) (System, error) {
+	parts := strings.Split(spec, ",")
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+	if name == "" {
+		name = "us"
+	}
+	s, ok := systems[name]
+	if !ok {
+		return System{}, fmt.Errorf("unknown unit system %q (use %s)", parts[0], strings.Join(Names, ", "))
+	}
+
+	for _, p := range parts[1:] {
+		quantity, unit, ok := strings.Cut(p, "=")
+		if !ok {
+			return System{}, fmt.Errorf("invalid unit override %q (want quantity=unit)", p)
+		}
+		var err error
+		if s, err = s.Override(quantity, unit); err != nil {
+			return System{}, err
+		}
+	}
+	return s, nil
+}
+
+// Resolve parses a system name and applies per-quantity overrides, as
+// stored in the config file.
+func Resolve(name string, overrides map[string]string) (System, error) {
+	s, err := Parse(name)
+	if err != nil {
+		return System{}, err
+	}
+	quantities := make([]string, 0, len(overrides))
+	for q := range overrides {
+		quantities = append(quantities, q)
+	}
+	sort.Strings(quantities)
+	for _, q := range quantities {
+		if s, err = s.Override(q, overrides[q]); err != nil {
+			return System{}, err
+		}
+	}
+	return s, nil
+}
+
+// Override returns s with the unit for one quantity (temperature, speed,
+// distance or pressure) replaced.
+func (s System) Override(quantity, unit string) (System, error) {
+	unit = strings.TrimSpace(unit)
+	switch strings.ToLower(strings.TrimSpace(quantity)) {
+	case "temperature", "temp":
+		t, err := ParseTemperature(unit)
+		s.Temperature = t
+		return s, err
+	case "speed", "wind":
+		v, err := ParseSpeed(unit)
+		s.Speed = v
+		return s, err
+	case "distance", "visibility":
+		d, err := ParseDistance(unit)
+		s.Distance = d
+		return s, err
+	case "pressure":
+		p, err := ParsePressure(unit)
+		s.Pressure = p
+		return s, err
+	}
+	return s, fmt.Errorf("unknown quantity %q (use temperature, speed, distance or pressure)", quantity)
+}
+
+// ParseTemperature parses a temperature unit such as "F" or "°C".
+func ParseTemperature(s string) (Temperature, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, "°")) {
+	case "f", "fahrenheit":
+		return Fahrenheit, nil
+	case "c", "celsius":
+		return Celsius, nil
+	}
+	return "", fmt.Errorf("unknown temperature unit %q (use F or C)", s)
+}
+
+// ParseSpeed parses a speed unit such as "mph", "km/h", "kt" or "m/s".
+func ParseSpeed(s string) (Speed, error) {
+	switch strings.ToLower(s) {
+	case "mph":
+		return MPH, nil
+	case "kmh", "km/h", "kph":
+		return KMH, nil
+	case "kt", "kts", "kn", "knots":
+		return Knots, nil
+	case "ms", "m/s", "mps":
+		return MPS, nil
+	}
+	return "", fmt.Errorf("unknown speed unit %q (use mph, kmh, kt or ms)", s)
+}
+
+// ParseDistance parses a distance unit, "mi" or "km".
+func ParseDistance(s string) (Distance, error) {
+	switch strings.ToLower(s) {
+	case "mi", "miles":
+		return Miles, nil
+	case "km", "kilometers", "kilometres":
+		return Kilometers, nil
+	}
+	return "", fmt.Errorf("unknown distance unit %q (use mi or km)", s)
+}
+
+// ParsePressure parses a pressure unit, "inHg" or "hPa" (or "mb").
+func ParsePressure(s string) (Pressure, error) {
+	switch strings.ToLower(s) {
+	case "inhg", "in":
+		return InHg, nil
+	case "hpa", "mb", "mbar":
+		return HPa, nil
+	}
+	return "", fmt.Errorf("unknown pressure unit %q (use inHg or hPa)", s)
+}
+
+// Convert converts a temperature in °C.
+func (u Temperature) Convert(c float64) float64 {
+	if u == Fahrenheit {
+		return c*9.0/5.0 + 32.0
+	}
+	return c
+}
+
+// Label returns the unit symbol, e.g. "°F".
+func (u Temperature) Label() string {
+	return "°" + string(u)
+}
+
+// Format converts a temperature in °C and formats it to whole degrees.
+func (u Temperature) Format(c float64) string {
+	return fmt.Sprintf("%.0f", u.Convert(c))
+}
+
+// Convert converts a speed in km/h.
+func (u Speed) Convert(kmh float64) float64 {
+	switch u {
+	case MPH:
+		return kmh * 0.621371
+	case Knots:
+		return kmh / 1.852
+	case MPS:
+		return kmh / 3.6
+	}
+	return kmh
+}
+
+// Label returns the unit symbol, e.g. "mph".
+func (u Speed) Label() string {
+	switch u {
+	case KMH:
+		return "km/h"
+	case MPS:
+		return "m/s"
+	}
+	return string(u)
+}
+
+// Format converts a speed in km/h and formats it to whole units.
+func (u Speed) Format(kmh float64) string {
+	return fmt.Sprintf("%.0f", u.Convert(kmh))
+}
+
+// Convert converts a distance in metres.
+func (u Distance) Convert(m float64) float64 {
+	if u == Miles {
+		return m / 1609.34
+	}
+	return m / 1000
+}
+
+// Label returns the unit symbol, e.g. "mi".
+func (u Distance) Label() string {
+	return string(u)
+}
+
+// Format converts a distance in metres and formats it to one decimal.
+func (u Distance) Format(m float64) string {
+	return fmt.Sprintf("%.1f", u.Convert(m))
+}
+
+// Height converts a height in metres to feet or metres, returning the
+// value and its unit label.
+func (u Distance) Height(m float64) (float64, string) {
+	if u == Miles {
+		return m * 3.28084, "ft"
+	}
+	return m, "m"
+}
+
+// Precipitation converts an amount in millimetres to inches or
+// millimetres, returning the value and its unit label.
+func (u Distance) Precipitation(mm float64) (float64, string) {
+	if u == Miles {
+		return mm / 25.4, "in"
+	}
+	return mm, "mm"
+}
+
+// FormatHeight converts a height in metres and formats it to whole feet
+// or metres.
+func (u Distance) FormatHeight(m float64) string {
+	v, _ := u.Height(m)
+	return fmt.Sprintf("%.0f", v)
+}
+
+// FormatPrecipitation converts an amount in millimetres and formats it to
+// hundredths of an inch or tenths of a millimetre.
+func (u Distance) FormatPrecipitation(mm float64) string {
+	v, unit := u.Precipitation(mm)
+	if unit == "in" {
+		return fmt.Sprintf("%.2f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// Convert converts a pressure in Pa.
+func (u Pressure) Convert(pa float64) float64 {
+	if u == InHg {
+		return pa / 3386.39
+	}
+	return pa / 100
+}
+
+// Label returns the unit symbol, e.g. "inHg".
+func (u Pressure) Label() string {
+	return string(u)
+}
+
+// Format converts a pressure in Pa and formats it to the precision
+// customary for the unit.
+func (u Pressure) Format(pa float64) string {
+	if u == InHg {
+		return fmt.Sprintf("%.2f", u.Convert(pa))
+	}
+	return fmt.Sprintf("%.0f", u.Convert(pa))
+}
+
+// FormatVaporPressure converts a vapor pressure in Pa and formats it to
+// hundredths of an inch of mercury or tenths of a hectopascal, since it's
+// far smaller than the barometric pressure.
+func (u Pressure) FormatVaporPressure(pa float64) string {
+	if u == InHg {
+		return fmt.Sprintf("%.2f", u.Convert(pa))
+	}
+	return fmt.Sprintf("%.1f", u.Convert(pa))
+}
@@ -0,0 +1,121 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    System
+		wantErr bool
+	}{
+		{"", US, false},
+		{"us", US, false},
+		{"Imperial", US, false},
+		{"si", SI, false},
+		{"metric", Metric, false},
+		{"aviation", Aviation, false},
+		{"metric,speed=kt", System{Celsius, Knots, Kilometers, HPa}, false},
+		{"aviation, temperature=°F, pressure=mb", System{Fahrenheit, Knots, Miles, HPa}, false},
+		{"nautical", System{}, true},
+		{"metric,speed", System{}, true},
+		{"metric,speed=furlongs", System{}, true},
+		{"metric,altitude=ft", System{}, true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	got, err := Resolve("metric", map[string]string{"pressure": "inHg", "speed": "m/s"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := System{Celsius, MPS, Kilometers, InHg}
+	if got != want {
+		t.Errorf("Resolve() = %+v, want %+v", got, want)
+	}
+
+	if _, err := Resolve("metric", map[string]string{"pressure": "psi"}); err == nil {
+		t.Error("Resolve() with invalid override expected error")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"0C in F", Fahrenheit.Convert(0), 32},
+		{"100C in F", Fahrenheit.Convert(100), 212},
+		{"20C in C", Celsius.Convert(20), 20},
+		{"100kmh in mph", MPH.Convert(100), 62.1371},
+		{"1.852kmh in kt", Knots.Convert(1.852), 1},
+		{"36kmh in m/s", MPS.Convert(36), 10},
+		{"50kmh in kmh", KMH.Convert(50), 50},
+		{"1609.34m in mi", Miles.Convert(1609.34), 1},
+		{"16000m in km", Kilometers.Convert(16000), 16},
+		{"101325Pa in inHg", InHg.Convert(101325), 29.92},
+		{"101325Pa in hPa", HPa.Convert(101325), 1013.25},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 0.01 {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestFormatAndLabel(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{Fahrenheit.Format(20), "68"},
+		{Fahrenheit.Label(), "°F"},
+		{Celsius.Label(), "°C"},
+		{Knots.Format(37.04), "20"},
+		{Knots.Label(), "kt"},
+		{KMH.Label(), "km/h"},
+		{MPS.Label(), "m/s"},
+		{Miles.Format(16093.4), "10.0"},
+		{Kilometers.Label(), "km"},
+		{InHg.Format(101325), "29.92"},
+		{HPa.Format(101325), "1013"},
+		{InHg.FormatVaporPressure(420), "0.12"},
+		{HPa.FormatVaporPressure(420), "4.2"},
+		{Miles.FormatHeight(1220), "4003"},
+		{Kilometers.FormatHeight(1220), "1220"},
+		{Miles.FormatPrecipitation(2.54), "0.10"},
+		{Kilometers.FormatPrecipitation(2.54), "2.5"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestDistance_HeightAndPrecipitation(t *testing.T) {
+	if v, unit := Miles.Height(1000); unit != "ft" || math.Abs(v-3280.84) > 0.01 {
+		t.Errorf("Miles.Height(1000) = %v %s, want 3280.84 ft", v, unit)
+	}
+	if v, unit := Kilometers.Height(1000); unit != "m" || v != 1000 {
+		t.Errorf("Kilometers.Height(1000) = %v %s, want 1000 m", v, unit)
+	}
+	if v, unit := Miles.Precipitation(25.4); unit != "in" || v != 1 {
+		t.Errorf("Miles.Precipitation(25.4) = %v %s, want 1 in", v, unit)
+	}
+	if v, unit := Kilometers.Precipitation(5); unit != "mm" || v != 5 {
+		t.Errorf("Kilometers.Precipitation(5) = %v %s, want 5 mm", v, unit)
+	}
+}